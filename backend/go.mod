@@ -1,3 +1,5 @@
 module github.com/texas-holdem/backend
 
 go 1.21
+
+require github.com/gorilla/websocket v1.5.3
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/texas-holdem/backend/internal/poker"
@@ -13,15 +15,21 @@ func (s *Server) handleEvaluate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var req struct {
-		HoleCards     []string `json:"hole_cards"`
+		Variant        string   `json:"variant"` // "holdem" (default), "plo4", "plo5", "plo8" or "6plus"; see poker.ParseVariant for aliases
+		HoleCards      []string `json:"hole_cards"`
 		CommunityCards []string `json:"community_cards"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "invalid JSON")
 		return
 	}
-	if len(req.HoleCards) != 2 {
-		respondError(w, http.StatusBadRequest, "need exactly 2 hole cards")
+	variant, err := poker.ParseVariant(req.Variant)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.HoleCards) != variant.HoleCards() {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("need exactly %d hole cards", variant.HoleCards()))
 		return
 	}
 	if len(req.CommunityCards) != 5 {
@@ -40,16 +48,20 @@ func (s *Server) handleEvaluate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := poker.EvaluateBestHand(hole, community)
+	result, err := variant.EvaluateBestHand(hole, community)
 	if err != nil {
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	respondJSON(w, http.StatusOK, map[string]any{
-		"best_hand":  cardsToStrings(result.BestHand),
-		"rank":       int(result.Rank),
-		"rank_name":  result.RankName,
-	})
+	resp := map[string]any{
+		"best_hand": cardsToStrings(result.BestHand),
+		"rank":      int(result.Rank),
+		"rank_name": result.RankName,
+	}
+	if variant.HiLo() {
+		resp["low"] = lowToJSON(result.Low)
+	}
+	respondJSON(w, http.StatusOK, resp)
 }
 
 func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
@@ -58,12 +70,13 @@ func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	var req struct {
-		Hand1 struct {
-			HoleCards     []string `json:"hole_cards"`
+		Variant string `json:"variant"`
+		Hand1   struct {
+			HoleCards      []string `json:"hole_cards"`
 			CommunityCards []string `json:"community_cards"`
 		} `json:"hand1"`
 		Hand2 struct {
-			HoleCards     []string `json:"hole_cards"`
+			HoleCards      []string `json:"hole_cards"`
 			CommunityCards []string `json:"community_cards"`
 		} `json:"hand2"`
 	}
@@ -71,6 +84,11 @@ func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "invalid JSON")
 		return
 	}
+	variant, err := poker.ParseVariant(req.Variant)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	hole1, err := poker.ParseCards(req.Hand1.HoleCards)
 	if err != nil {
 		respondError(w, http.StatusBadRequest, "hand1: "+err.Error())
@@ -91,12 +109,13 @@ func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, "hand2 community: "+err.Error())
 		return
 	}
-	if len(hole1) != 2 || len(comm1) != 5 || len(hole2) != 2 || len(comm2) != 5 {
-		respondError(w, http.StatusBadRequest, "each hand needs 2 hole + 5 community cards")
+	n := variant.HoleCards()
+	if len(hole1) != n || len(comm1) != 5 || len(hole2) != n || len(comm2) != 5 {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("each hand needs %d hole + 5 community cards", n))
 		return
 	}
 
-	winner, h1, h2, err := poker.CompareHands(hole1, comm1, hole2, comm2)
+	winner, h1, h2, err := variant.CompareHands(hole1, comm1, hole2, comm2)
 	if err != nil {
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
@@ -108,29 +127,120 @@ func (s *Server) handleCompare(w http.ResponseWriter, r *http.Request) {
 		winnerStr = "hand2"
 	}
 	respondJSON(w, http.StatusOK, map[string]any{
-		"winner":   winnerStr,
-		"hand1":    map[string]any{"best_hand": cardsToStrings(h1.BestHand), "rank_name": h1.RankName},
-		"hand2":    map[string]any{"best_hand": cardsToStrings(h2.BestHand), "rank_name": h2.RankName},
+		"winner": winnerStr,
+		"hand1":  map[string]any{"best_hand": cardsToStrings(h1.BestHand), "rank_name": h1.RankName},
+		"hand2":  map[string]any{"best_hand": cardsToStrings(h2.BestHand), "rank_name": h2.RankName},
 	})
 }
 
+func (s *Server) handleShowdown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		Variant        string   `json:"variant"`
+		CommunityCards []string `json:"community_cards"`
+		Players        []struct {
+			HoleCards []string `json:"hole_cards"`
+		} `json:"players"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	variant, err := poker.ParseVariant(req.Variant)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.CommunityCards) != 5 {
+		respondError(w, http.StatusBadRequest, "need exactly 5 community cards")
+		return
+	}
+	if len(req.Players) < 2 || len(req.Players) > 10 {
+		respondError(w, http.StatusBadRequest, "need 2-10 players")
+		return
+	}
+
+	board, err := poker.ParseCards(req.CommunityCards)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "community: "+err.Error())
+		return
+	}
+	players := make([][]poker.Card, len(req.Players))
+	for i, p := range req.Players {
+		if len(p.HoleCards) != variant.HoleCards() {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("player %d: need exactly %d hole cards", i+1, variant.HoleCards()))
+			return
+		}
+		players[i], err = poker.ParseCards(p.HoleCards)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("player %d: %v", i+1, err))
+			return
+		}
+	}
+
+	result, err := variant.Showdown(board, players)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	hands := make([]map[string]any, len(result.Hands))
+	for i, h := range result.Hands {
+		hands[i] = map[string]any{
+			"best_hand": cardsToStrings(h.BestHand),
+			"rank":      int(h.Rank),
+			"rank_name": h.RankName,
+		}
+		if variant.HiLo() {
+			hands[i]["low"] = lowToJSON(h.Low)
+		}
+	}
+	resp := map[string]any{
+		"players": hands,
+		"ranking": result.Ranking,
+		"winners": result.Winners,
+		"split":   result.Split(),
+		"shares":  result.Shares(),
+	}
+	if variant.HiLo() {
+		lowWinners := result.LowWinners
+		if lowWinners == nil {
+			lowWinners = []int{}
+		}
+		resp["low_winners"] = lowWinners
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
 func (s *Server) handleProbability(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 	var req struct {
+		Variant        string   `json:"variant"`
 		HoleCards      []string `json:"hole_cards"`
 		CommunityCards []string `json:"community_cards"`
+		DeadCards      []string `json:"dead_cards"` // out of play, never dealt
 		NumPlayers     int      `json:"num_players"`
 		NumSims        int      `json:"num_sims"`
+		OpponentRanges []string `json:"opponent_ranges"` // per opponent; "" deals random cards
+		Seed           int64    `json:"seed"`            // 0 or omitted picks a random seed
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		respondError(w, http.StatusBadRequest, "invalid JSON")
 		return
 	}
-	if len(req.HoleCards) != 2 {
-		respondError(w, http.StatusBadRequest, "need exactly 2 hole cards")
+	variant, err := poker.ParseVariant(req.Variant)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	n := variant.HoleCards()
+	if len(req.HoleCards) != n {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("need exactly %d hole cards", n))
 		return
 	}
 	if req.NumPlayers == 0 {
@@ -139,28 +249,220 @@ func (s *Server) handleProbability(w http.ResponseWriter, r *http.Request) {
 	if req.NumSims == 0 {
 		req.NumSims = 10000
 	}
+	// Checked up front so a bad num_sims fails even when the odds are
+	// enumerated.
+	if req.NumSims < 1 || req.NumSims > poker.MaxSims {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("num_sims must be 1-%d", poker.MaxSims))
+		return
+	}
 
-	hole, err := poker.ParseCards(req.HoleCards)
+	// Parsing every known card together rejects a card named twice.
+	known := append(append(append([]string{}, req.HoleCards...), req.CommunityCards...), req.DeadCards...)
+	cards, err := poker.ParseCards(known)
 	if err != nil {
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	community, err := poker.ParseCards(req.CommunityCards)
+	hole := cards[:n]
+	community := cards[n : n+len(req.CommunityCards)]
+	dead := cards[n+len(req.CommunityCards):]
+	ranges := make([]poker.Range, len(req.OpponentRanges))
+	for i, spec := range req.OpponentRanges {
+		if spec == "" {
+			continue
+		}
+		if ranges[i], err = poker.ParseRange(spec); err != nil {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("opponent %d: %v", i+1, err))
+			return
+		}
+	}
+
+	// Small runouts, opponent ranges included, are cheaper to walk exactly
+	// than to sample.
+	cfg := poker.SimConfig{
+		Variant:    variant,
+		Hole:       hole,
+		Community:  community,
+		Dead:       dead,
+		NumPlayers: req.NumPlayers,
+		NumSims:    req.NumSims,
+		Ranges:     ranges,
+		Seed:       req.Seed,
+	}
+	var result poker.EquityResult
+	if cfg.ExactDeals() <= s.exactThreshold/int64(variant.Evaluations()) {
+		result, err = poker.Enumerate(r.Context(), cfg)
+	} else {
+		// Simulations stop when the client goes away.
+		result, err = poker.Simulate(r.Context(), cfg)
+	}
+	if r.Context().Err() != nil {
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	mode := "monte_carlo"
+	if result.Exact {
+		mode = "exact"
+	}
+	resp := map[string]any{
+		"win_probability":  result.Win,
+		"tie_probability":  result.Tie,
+		"loss_probability": result.Loss,
+		"equity":           result.Equity,
+		"win_std_err":      result.WinStdErr,
+		"tie_std_err":      result.TieStdErr,
+		"loss_std_err":     result.LossStdErr,
+		"equity_std_err":   result.EquityStdErr,
+		"mode":             mode,
+		"samples":          result.Samples,
+		"num_sims":         req.NumSims,
+		"num_players":      req.NumPlayers,
+		// How often each hand finishes as each category, High Card first,
+		// and how it fares when it does.
+		"ranks":          result.Ranks,
+		"opponent_ranks": result.OpponentRanks,
+	}
+	if !result.Exact {
+		resp["seed"] = result.Seed
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleEquity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		Variant string `json:"variant"`
+		Players []struct {
+			HoleCards []string `json:"hole_cards"`
+			Range     string   `json:"range"` // Hold'em only
+		} `json:"players"`
+		CommunityCards []string `json:"community_cards"`
+		DeadCards      []string `json:"dead_cards"`
+		NumSims        int      `json:"num_sims"`
+		Seed           int64    `json:"seed"` // 0 or omitted picks a random seed
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if len(req.Players) < 2 || len(req.Players) > 10 {
+		respondError(w, http.StatusBadRequest, "need 2-10 players")
+		return
+	}
+	if req.NumSims == 0 {
+		req.NumSims = 10000
+	}
+	if req.NumSims < 1 || req.NumSims > poker.MaxSims {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("num_sims must be 1-%d", poker.MaxSims))
+		return
+	}
+	variant, err := poker.ParseVariant(req.Variant)
 	if err != nil {
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	prob, err := poker.WinProbability(hole, community, req.NumPlayers, req.NumSims)
+	cfg := poker.EquityConfig{
+		Variant: variant,
+		Players: make([]poker.Range, len(req.Players)),
+		NumSims: req.NumSims,
+		Seed:    req.Seed,
+	}
+	for i, p := range req.Players {
+		switch {
+		case len(p.HoleCards) > 0 && p.Range != "":
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("player %d: give hole_cards or range, not both", i+1))
+			return
+		case len(p.HoleCards) > 0:
+			if len(p.HoleCards) != variant.HoleCards() {
+				respondError(w, http.StatusBadRequest, fmt.Sprintf("player %d: need exactly %d hole cards", i+1, variant.HoleCards()))
+				return
+			}
+			hole, err := poker.ParseCards(p.HoleCards)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, fmt.Sprintf("player %d: %v", i+1, err))
+				return
+			}
+			cfg.Players[i] = poker.HandRange(hole...)
+		case p.Range != "":
+			rng, err := poker.ParseRange(p.Range)
+			if err != nil {
+				respondError(w, http.StatusBadRequest, fmt.Sprintf("player %d: %v", i+1, err))
+				return
+			}
+			cfg.Players[i] = rng
+		default:
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("player %d: need hole_cards or range", i+1))
+			return
+		}
+	}
+	if cfg.Community, err = poker.ParseCards(req.CommunityCards); err != nil {
+		respondError(w, http.StatusBadRequest, "community: "+err.Error())
+		return
+	}
+	if cfg.Dead, err = poker.ParseCards(req.DeadCards); err != nil {
+		respondError(w, http.StatusBadRequest, "dead: "+err.Error())
+		return
+	}
+
+	results, err := s.equity(r.Context(), cfg)
+	if r.Context().Err() != nil {
+		return
+	}
 	if err != nil {
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	respondJSON(w, http.StatusOK, map[string]any{
-		"win_probability": prob,
-		"num_sims":        req.NumSims,
-		"num_players":     req.NumPlayers,
-	})
+	players := make([]map[string]any, len(results))
+	for i, res := range results {
+		players[i] = map[string]any{
+			"win":            res.Win,
+			"tie":            res.Tie,
+			"loss":           res.Loss,
+			"equity":         res.Equity,
+			"win_std_err":    res.WinStdErr,
+			"tie_std_err":    res.TieStdErr,
+			"loss_std_err":   res.LossStdErr,
+			"equity_std_err": res.EquityStdErr,
+		}
+	}
+	resp := map[string]any{
+		"players": players,
+		"mode":    "monte_carlo",
+		"samples": results[0].Samples,
+	}
+	if results[0].Exact {
+		resp["mode"] = "exact"
+	} else {
+		resp["seed"] = results[0].Seed
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// equity enumerates cfg exactly when that is cheap enough, and simulates it
+// otherwise.
+func (s *Server) equity(ctx context.Context, cfg poker.EquityConfig) ([]poker.EquityResult, error) {
+	if poker.ExactDeals(cfg) <= s.exactThreshold/int64(cfg.Variant.Evaluations()) {
+		return poker.EnumerateEquity(ctx, cfg)
+	}
+	return poker.SimulateEquity(ctx, cfg)
+}
+
+// lowToJSON describes a qualifying low, or gives null when there is none.
+func lowToJSON(low *poker.LowHand) any {
+	if low == nil {
+		return nil
+	}
+	return map[string]any{
+		"cards": cardsToStrings(low.Cards),
+		"name":  low.Name,
+	}
 }
 
 func cardsToStrings(c []poker.Card) []string {
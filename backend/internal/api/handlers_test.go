@@ -0,0 +1,215 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func postJSON(t *testing.T, s *Server, path, body string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s: decoding %q: %v", path, rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+func TestHandleEquity(t *testing.T) {
+	s := New()
+	code, resp := postJSON(t, s, "/api/v1/equity", `{
+		"players": [{"hole_cards": ["HA", "HK"]}, {"hole_cards": ["SQ", "DQ"]}],
+		"community_cards": ["H7", "C2", "HJ"]
+	}`)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, body %v", code, resp)
+	}
+	if resp["mode"] != "exact" {
+		t.Errorf("mode = %v, want exact", resp["mode"])
+	}
+	players := resp["players"].([]any)
+	sum := 0.0
+	for _, p := range players {
+		sum += p.(map[string]any)["equity"].(float64)
+	}
+	if len(players) != 2 || sum < 0.999999 || sum > 1.000001 {
+		t.Errorf("players = %v, want 2 equities summing to 1", players)
+	}
+
+	code, resp = postJSON(t, s, "/api/v1/equity", `{
+		"players": [{"hole_cards": ["HA", "HK"]}, {"range": "QQ+, AKs"}, {"range": "22+"}],
+		"num_sims": 2000,
+		"seed": 11
+	}`)
+	if code != http.StatusOK || resp["mode"] != "monte_carlo" || resp["seed"].(float64) != 11 {
+		t.Errorf("ranges preflop: status %d, body %v", code, resp)
+	}
+
+	for _, body := range []string{
+		`{"players": [{"hole_cards": ["HA", "HK"]}]}`,
+		`{"players": [{"hole_cards": ["HA", "HK"]}, {"hole_cards": ["HA", "DQ"]}]}`,
+		`{"players": [{"hole_cards": ["HA", "HK"]}, {}]}`,
+		`{"players": [{"hole_cards": ["HA", "HK"]}, {"range": "XX"}]}`,
+	} {
+		if code, resp := postJSON(t, s, "/api/v1/equity", body); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, body %v, want 400", body, code, resp)
+		}
+	}
+	// Players are numbered from 1, as in the request's order.
+	if _, resp := postJSON(t, s, "/api/v1/equity", `{"players": [{"hole_cards": ["HA", "HK"]}, {}]}`); resp["error"] != "player 2: need hole_cards or range" {
+		t.Errorf("error = %v, want it to name player 2", resp["error"])
+	}
+}
+
+func TestHandleProbability_DeadCards(t *testing.T) {
+	s := New()
+	code, resp := postJSON(t, s, "/api/v1/probability", `{
+		"hole_cards": ["SA", "SK"],
+		"community_cards": ["S7", "S2", "D9", "CT"],
+		"dead_cards": ["S3", "S4", "S5"]
+	}`)
+	if code != http.StatusOK || resp["mode"] != "exact" {
+		t.Fatalf("status = %d, body %v", code, resp)
+	}
+	if want := float64(43 * 42 / 2 * 41); resp["samples"] != want {
+		t.Errorf("samples = %v, want %v", resp["samples"], want)
+	}
+	ranks := resp["ranks"].([]any)
+	if len(ranks) != 10 || len(resp["opponent_ranks"].([]any)) != 10 {
+		t.Fatalf("ranks = %v", ranks)
+	}
+	// Three spades are dead: six make the flush.
+	if flush := ranks[5].(map[string]any); flush["rank_name"] != "Flush" || math.Abs(flush["probability"].(float64)-6.0/43) > 1e-9 {
+		t.Errorf("flush = %v", flush)
+	}
+
+	// A range opponent on the turn is walked exactly too.
+	code, resp = postJSON(t, s, "/api/v1/probability", `{
+		"hole_cards": ["SA", "SK"],
+		"community_cards": ["S7", "S2", "D9", "CT"],
+		"opponent_ranges": ["QQ+"]
+	}`)
+	if code != http.StatusOK || resp["mode"] != "exact" {
+		t.Errorf("range opponent: status %d, body %v", code, resp)
+	}
+
+	for _, body := range []string{
+		`{"hole_cards": ["SA", "SK"], "dead_cards": ["SA"]}`,
+		`{"hole_cards": ["SA", "SK"], "community_cards": ["S7"], "dead_cards": ["S7"]}`,
+		`{"hole_cards": ["SA", "SK"], "dead_cards": ["XX"]}`,
+		`{"hole_cards": ["SA", "SK"], "community_cards": ["S7", "S2", "D9", "CT"], "num_sims": 2000000}`, // checked though enumerated
+	} {
+		if code, resp := postJSON(t, s, "/api/v1/probability", body); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, body %v, want 400", body, code, resp)
+		}
+	}
+}
+
+func TestHandleVariants(t *testing.T) {
+	s := New()
+	code, resp := postJSON(t, s, "/api/v1/evaluate", `{
+		"variant": "plo4",
+		"hole_cards": ["HA", "SA", "DK", "CK"],
+		"community_cards": ["H2", "H7", "H9", "HJ", "D3"]
+	}`)
+	if code != http.StatusOK || resp["rank_name"] != "One Pair" {
+		t.Errorf("evaluate plo4: status %d, body %v, want One Pair", code, resp)
+	}
+
+	code, resp = postJSON(t, s, "/api/v1/compare", `{
+		"variant": "plo5",
+		"hand1": {"hole_cards": ["HA", "SA", "DK", "CK", "C9"], "community_cards": ["H2", "H7", "H9", "HJ", "D3"]},
+		"hand2": {"hole_cards": ["SK", "SQ", "D4", "C4", "D8"], "community_cards": ["S2", "S7", "ST", "C3", "D5"]}
+	}`)
+	if code != http.StatusOK || resp["winner"] != "hand2" {
+		t.Errorf("compare plo5: status %d, body %v, want hand2", code, resp)
+	}
+
+	code, resp = postJSON(t, s, "/api/v1/probability", `{
+		"variant": "plo4",
+		"hole_cards": ["HA", "SA", "DK", "CK"],
+		"community_cards": ["H2", "H7", "H9"],
+		"num_sims": 2000,
+		"seed": 4
+	}`)
+	if code != http.StatusOK || resp["mode"] != "monte_carlo" {
+		t.Errorf("probability plo4: status %d, body %v", code, resp)
+	}
+
+	for path, body := range map[string]string{
+		"/api/v1/evaluate":    `{"variant": "razz", "hole_cards": ["HA", "SA"], "community_cards": ["H2", "H7", "H9", "HJ", "D3"]}`,
+		"/api/v1/showdown":    `{"variant": "plo4", "community_cards": ["H2", "H7", "H9", "HJ", "D3"], "players": [{"hole_cards": ["HA", "SA"]}, {"hole_cards": ["HK", "SK"]}]}`,
+		"/api/v1/probability": `{"variant": "plo4", "hole_cards": ["HA", "SA", "DK", "CK"], "opponent_ranges": ["QQ+"]}`,
+	} {
+		if code, resp := postJSON(t, s, path, body); code != http.StatusBadRequest {
+			t.Errorf("%s %s: status %d, body %v, want 400", path, body, code, resp)
+		}
+	}
+}
+
+func TestHandleShowdown_HiLo(t *testing.T) {
+	s := New()
+	code, resp := postJSON(t, s, "/api/v1/showdown", `{
+		"variant": "plo8",
+		"community_cards": ["H3", "C5", "D7", "SK", "SQ"],
+		"players": [
+			{"hole_cards": ["SA", "ST", "HK", "DK"]},
+			{"hole_cards": ["HA", "D2", "C9", "D9"]}
+		]
+	}`)
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, body %v", code, resp)
+	}
+	if resp["split"] != true || fmt.Sprint(resp["low_winners"]) != "[1]" || fmt.Sprint(resp["shares"]) != "[0.5 0.5]" {
+		t.Errorf("body %v, want player 0 high and player 1 low", resp)
+	}
+	players := resp["players"].([]any)
+	if low := players[1].(map[string]any)["low"].(map[string]any); low["name"] != "7-5-3-2-A" {
+		t.Errorf("player 1 low = %v", low)
+	}
+	if players[0].(map[string]any)["low"] != nil {
+		t.Errorf("player 0 low = %v, want null", players[0])
+	}
+
+	code, resp = postJSON(t, s, "/api/v1/evaluate", `{
+		"variant": "omaha8",
+		"hole_cards": ["HA", "D2", "C9", "D9"],
+		"community_cards": ["H3", "C5", "D7", "SK", "SQ"]
+	}`)
+	if code != http.StatusOK || resp["low"].(map[string]any)["name"] != "7-5-3-2-A" {
+		t.Errorf("evaluate plo8: status %d, body %v", code, resp)
+	}
+}
+
+func TestHandleShortDeck(t *testing.T) {
+	s := New()
+	code, resp := postJSON(t, s, "/api/v1/evaluate", `{
+		"variant": "6plus",
+		"hole_cards": ["HA", "D6"],
+		"community_cards": ["C7", "S8", "H9", "DK", "CJ"]
+	}`)
+	if code != http.StatusOK || resp["rank_name"] != "Straight" {
+		t.Errorf("evaluate 6plus: status %d, body %v, want Straight", code, resp)
+	}
+
+	code, resp = postJSON(t, s, "/api/v1/probability", `{
+		"variant": "shortdeck",
+		"hole_cards": ["SA", "SK"],
+		"community_cards": ["S7", "S6", "D9"]
+	}`)
+	if code != http.StatusOK || resp["mode"] != "exact" || resp["samples"] != float64(31*30/2*29*28/2) {
+		t.Errorf("probability 6plus: status %d, body %v", code, resp)
+	}
+
+	code, resp = postJSON(t, s, "/api/v1/probability", `{"variant": "6plus", "hole_cards": ["SA", "S2"]}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("deuce in short deck: status %d, body %v, want 400", code, resp)
+	}
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// handleOuts lists the cards that improve a hand on the flop or the turn,
+// by the category they make, and the chance of hitting by the river.
+func (s *Server) handleOuts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		Variant        string   `json:"variant"`
+		HoleCards      []string `json:"hole_cards"`
+		CommunityCards []string `json:"community_cards"` // the flop, or the flop and the turn
+		Opponent       []string `json:"opponent"`        // optional: count only cards that beat this hand
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	cfg := poker.OutsConfig{}
+	var err error
+	if cfg.Variant, err = poker.ParseVariant(req.Variant); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if cfg.Hole, err = poker.ParseCards(req.HoleCards); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if cfg.Community, err = poker.ParseCards(req.CommunityCards); err != nil {
+		respondError(w, http.StatusBadRequest, "community: "+err.Error())
+		return
+	}
+	if req.Opponent != nil {
+		if cfg.Opponent, err = poker.ParseCards(req.Opponent); err != nil {
+			respondError(w, http.StatusBadRequest, "opponent: "+err.Error())
+			return
+		}
+	}
+
+	res, err := poker.Outs(cfg)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	outs := make([]map[string]any, len(res.Outs))
+	for i, g := range res.Outs {
+		outs[i] = map[string]any{
+			"rank":      int(g.Rank),
+			"rank_name": g.RankName,
+			"cards":     cardsToStrings(g.Cards),
+			"count":     len(g.Cards),
+		}
+	}
+	resp := map[string]any{
+		"hand":      outsHand(res.Hand),
+		"outs":      outs,
+		"count":     res.Count,
+		"unseen":    res.Unseen,
+		"next_card": res.NextCard,
+		"by_river":  res.ByRiver,
+	}
+	if res.Opponent != nil {
+		resp["opponent"] = outsHand(*res.Opponent)
+		resp["behind"] = res.Behind
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func outsHand(h poker.EvaluatedHand) map[string]any {
+	return map[string]any{
+		"best_hand": cardsToStrings(h.BestHand),
+		"rank":      int(h.Rank),
+		"rank_name": h.RankName,
+	}
+}
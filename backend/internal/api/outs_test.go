@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHandleOuts(t *testing.T) {
+	s := New()
+	code, resp := postJSON(t, s, "/api/v1/outs", `{"hole_cards": ["H8", "H9"], "community_cards": ["HT", "DJ", "C2"]}`)
+	if code != http.StatusOK {
+		t.Fatalf("status %d: %v", code, resp)
+	}
+	// Open-ended straight draw: four 7s and four queens, one of each a heart.
+	outs := resp["outs"].([]any)
+	if straight := outs[0].(map[string]any); straight["rank_name"] != "Straight" || straight["count"] != 8.0 {
+		t.Errorf("outs = %v", outs)
+	}
+	if _, ok := resp["behind"]; ok || resp["unseen"] != 47.0 {
+		t.Errorf("response = %v", resp)
+	}
+
+	code, resp = postJSON(t, s, "/api/v1/outs", `{"hole_cards": ["H8", "H9"], "community_cards": ["HT", "DJ", "C2", "S2"], "opponent": ["ST", "CJ"]}`)
+	if code != http.StatusOK || resp["behind"] != true || resp["count"] != 8.0 || resp["opponent"].(map[string]any)["rank_name"] != "Two Pair" {
+		t.Errorf("against two pair: %d %v", code, resp)
+	}
+
+	for _, body := range []string{
+		`{"hole_cards": ["H8", "H9"], "community_cards": ["HT", "DJ"]}`,
+		`{"hole_cards": ["H8", "H9"], "community_cards": ["HT", "DJ", "C2"], "opponent": ["H8", "CJ"]}`,
+		`{"hole_cards": ["H8"], "community_cards": ["HT", "DJ", "C2"]}`,
+		`{"hole_cards": ["H8", "H9"], "community_cards": ["HT", "DJ", "XX"]}`,
+	} {
+		if code, resp := postJSON(t, s, "/api/v1/outs", body); code != http.StatusBadRequest {
+			t.Errorf("%s: %d %v", body, code, resp)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/texas-holdem/backend/internal/game"
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// boardCards is how many board cards each betting street sees.
+var boardCards = map[game.Street]int{game.Preflop: 0, game.Flop: 3, game.Turn: 4, game.River: 5}
+
+// handleReplay steps through a recorded hand, given by hand_id (as player
+// may see it) or as a history in the body, and reports each player's equity
+// as every street began.
+func (s *Server) handleReplay(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		HandID  string            `json:"hand_id"`
+		Player  string            `json:"player"` // with hand_id: whose hole cards to use
+		Token   string            `json:"token"`  // with player: their seat token
+		History *game.HandHistory `json:"history"`
+		NumSims int               `json:"num_sims"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+	if (req.HandID == "") == (req.History == nil) {
+		respondError(w, http.StatusBadRequest, "give hand_id or history")
+		return
+	}
+	if req.NumSims == 0 {
+		req.NumSims = 10000
+	}
+	if req.NumSims < 1 || req.NumSims > poker.MaxSims {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("num_sims must be 1-%d", poker.MaxSims))
+		return
+	}
+	var h game.HandHistory
+	if req.History != nil {
+		h = *req.History
+	} else {
+		stored, err := s.tables.Hand(req.HandID)
+		if err != nil {
+			respondTableError(w, err)
+			return
+		}
+		if h, err = handFor(stored, req.Player, req.Token); err != nil {
+			respondTableError(w, err)
+			return
+		}
+	}
+
+	steps, err := game.Replay(h)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	streets := []map[string]any{}
+	for street := game.Preflop; street <= game.River && len(h.Board) >= boardCards[street]; street++ {
+		cfg := poker.EquityConfig{
+			Variant:   h.Config.Variant,
+			Community: h.Board[:boardCards[street]],
+			NumSims:   req.NumSims,
+		}
+		var live []game.HistorySeat
+		for _, st := range h.Seats {
+			if folded(h, st.Seat, street) {
+				cfg.Dead = append(cfg.Dead, st.Hole...)
+				continue
+			}
+			live = append(live, st)
+			if st.Hole != nil {
+				cfg.Players = append(cfg.Players, poker.HandRange(st.Hole...))
+			} else {
+				cfg.Players = append(cfg.Players, nil) // dealt at random
+			}
+		}
+		if len(live) < 2 {
+			break
+		}
+		results, err := s.equity(r.Context(), cfg)
+		if r.Context().Err() != nil {
+			return
+		}
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		players := make([]map[string]any, len(live))
+		for i, st := range live {
+			players[i] = map[string]any{
+				"seat":           st.Seat,
+				"player":         st.Player,
+				"known":          st.Hole != nil,
+				"win":            results[i].Win,
+				"tie":            results[i].Tie,
+				"equity":         results[i].Equity,
+				"equity_std_err": results[i].EquityStdErr,
+			}
+		}
+		mode := "monte_carlo"
+		if results[0].Exact {
+			mode = "exact"
+		}
+		streets = append(streets, map[string]any{
+			"street":  street.String(),
+			"board":   cardsToStrings(cfg.Community),
+			"mode":    mode,
+			"players": players,
+		})
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"hand_id": h.ID,
+		"steps":   steps,
+		"equity":  streets,
+		"result":  h.Result,
+	})
+}
+
+// folded reports whether seat folded before street began.
+func folded(h game.HandHistory, seat int, street game.Street) bool {
+	for _, a := range h.Actions {
+		if a.Seat == seat && a.Type == game.Fold && a.Street < street {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/texas-holdem/backend/internal/game"
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// playedHand plays AA against KK heads-up: a raise and a call, then a bet
+// and a fold on the flop.
+func playedHand(t *testing.T) game.HandHistory {
+	t.Helper()
+	s, err := game.NewTable(game.Config{SmallBlind: 1, BigBlind: 2, MaxSeats: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, _ = s.Sit(0, "alice", 100)
+	s, _ = s.Sit(1, "bob", 100)
+	top, _ := poker.ParseCards([]string{"HK", "HA", "DK", "DA", "C4", "D7", "C9", "S2"})
+	deck := append(top, (poker.Holdem.Deck() &^ poker.CardSetOf(top)).Cards()...)
+	if s, err = s.StartHand(deck); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range []game.Action{{Seat: 0, Type: game.Raise, Amount: 6}, {Seat: 1, Type: game.Call}, {Seat: 1, Type: game.Bet, Amount: 4}, {Seat: 0, Type: game.Fold}} {
+		if s, err = s.Apply(a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return *s.History
+}
+
+func TestHandleReplay(t *testing.T) {
+	s := New()
+	body, _ := json.Marshal(map[string]any{"history": playedHand(t)})
+	code, resp := postJSON(t, s, "/api/v1/replay", string(body))
+	if code != http.StatusOK {
+		t.Fatalf("status %d: %v", code, resp)
+	}
+	steps := resp["steps"].([]any)
+	if len(steps) != 5 {
+		t.Fatalf("%d steps, want the deal and 4 actions", len(steps))
+	}
+	flop := steps[2].(map[string]any)
+	if flop["street"] != "flop" || flop["pot"] != 12.0 || flop["to_act"] != 1.0 {
+		t.Errorf("after the call: %v", flop)
+	}
+
+	streets := resp["equity"].([]any)
+	if len(streets) != 2 {
+		t.Fatalf("equity at %d streets, want preflop and flop", len(streets))
+	}
+	for _, st := range streets {
+		players := st.(map[string]any)["players"].([]any)
+		aces, kings := players[0].(map[string]any), players[1].(map[string]any)
+		if sum := aces["equity"].(float64) + kings["equity"].(float64); math.Abs(sum-1) > 1e-9 || aces["equity"].(float64) < 0.75 {
+			t.Errorf("%v: aces %v, kings %v", st.(map[string]any)["street"], aces["equity"], kings["equity"])
+		}
+	}
+}
+
+func TestHandleReplay_StoredHand(t *testing.T) {
+	s := New()
+	h := playedHand(t)
+	h.ID = "t1-1"
+	h.Seats[1].Token = "bobs-token"
+	s.tables.SaveHand(h)
+
+	// Bob sees only his kings: the aces are an unknown hand.
+	code, resp := postJSON(t, s, "/api/v1/replay", `{"hand_id": "t1-1", "player": "bob", "token": "bobs-token", "num_sims": 2000}`)
+	if code != http.StatusOK {
+		t.Fatalf("status %d: %v", code, resp)
+	}
+	preflop := resp["equity"].([]any)[0].(map[string]any)["players"].([]any)
+	if alice := preflop[0].(map[string]any); alice["known"] != false || alice["equity"].(float64) > 0.5 {
+		t.Errorf("alice as bob sees her: %v", alice)
+	}
+
+	for body, want := range map[string]int{
+		`{"hand_id": "t1-2"}`:                  http.StatusNotFound,
+		`{"hand_id": "t1-1", "player": "bob"}`: http.StatusForbidden,
+		`{}`:                                   http.StatusBadRequest,
+		`{"history": {"config": {"small_blind": 1, "big_blind": 2, "max_seats": 2}, "seats": [{"seat": 0, "player": "a", "stack": 10}]}}`: http.StatusBadRequest,
+	} {
+		if code, resp := postJSON(t, s, "/api/v1/replay", body); code != want {
+			t.Errorf("%s: %d %v, want %d", body, code, resp, want)
+		}
+	}
+}
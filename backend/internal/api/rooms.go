@@ -0,0 +1,452 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/texas-holdem/backend/internal/deck"
+	"github.com/texas-holdem/backend/internal/game"
+	"github.com/texas-holdem/backend/internal/lobby"
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// room is a table being played over WebSocket: the clients watching it.
+// The table itself lives in the store. Every change to it happens under mu
+// and is broadcast before the lock is released, so clients see changes in
+// order.
+type room struct {
+	id      string
+	store   lobby.Store
+	mu      sync.Mutex
+	clients map[*client]bool
+
+	// The action timer plays for a player who does not act within timeout
+	// of their turn starting; 0 turns it off.
+	timeout time.Duration
+	timer   *time.Timer
+	turn    turn // the turn the timer runs for
+}
+
+// turn identifies one player's turn to act.
+type turn struct {
+	hand, seat, actions int // actions is how many moves the hand had before it
+}
+
+// errTurnOver stops a timer that fired after its turn was played.
+var errTurnOver = errors.New("the turn is over")
+
+// room returns the room for the stored table id.
+func (s *Server) room(id string) (*room, error) {
+	if _, err := s.tables.Get(id); err != nil {
+		return nil, err
+	}
+	s.roomsMu.Lock()
+	defer s.roomsMu.Unlock()
+	if rm, ok := s.rooms[id]; ok {
+		return rm, nil
+	}
+	rm := &room{
+		id:      id,
+		store:   s.tables,
+		clients: make(map[*client]bool),
+		timeout: s.actionTimeout,
+	}
+	s.rooms[id] = rm
+	return rm, nil
+}
+
+// join subscribes c as player and sends it a snapshot. A seated player
+// must give their seat token.
+func (rm *room) join(c *client, player, token string) error {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	t, err := rm.store.Get(rm.id)
+	if err != nil {
+		return err
+	}
+	seated := seatOf(t.State, player) >= 0
+	if seated {
+		if err := t.Authorize(player, token); err != nil {
+			return err
+		}
+	}
+	for other := range rm.clients {
+		if other.player == player && (other.token != "" || !seated) {
+			return &game.InvalidActionError{Msg: fmt.Sprintf("%s is already connected", player)}
+		}
+	}
+	c.player = player
+	if seated {
+		c.token = token
+	}
+	rm.clients[c] = true
+	c.sendJSON(map[string]any{"type": "snapshot", "table": view(t, c.viewer())})
+	return nil
+}
+
+// identity returns the player c joined as and the seat token it holds.
+func (rm *room) identity(c *client) (player, token string) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	return c.player, c.token
+}
+
+// leave unsubscribes c. Its seat, if any, stays taken.
+func (rm *room) leave(c *client) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	delete(rm.clients, c)
+}
+
+// handle plays one message from c.
+func (rm *room) handle(c *client, msg inbound) error {
+	player, token := rm.identity(c)
+	switch msg.Type {
+	case "sit":
+		_, err := rm.sit(c, msg.Seat, player, msg.BuyIn)
+		return err
+	case "stand":
+		_, err := rm.stand(player, token)
+		return err
+	case "seed":
+		return rm.seed(player, token, msg.Seed)
+	case "start":
+		return rm.start(player, token)
+	case "action":
+		return rm.act(player, token, msg.Action, msg.Amount)
+	}
+	return &poker.InvalidInputError{Msg: fmt.Sprintf("unknown message type %q", msg.Type)}
+}
+
+// update changes the stored table with fn under the room's lock and, when
+// it succeeds, has announce broadcast the events before everyone gets a
+// fresh snapshot.
+func (rm *room) update(fn func(lobby.Table) (lobby.Table, error), announce func(before, next lobby.Table)) (lobby.Table, error) {
+	rm.mu.Lock()
+	defer rm.mu.Unlock()
+	var before lobby.Table
+	t, err := rm.store.Update(rm.id, func(t lobby.Table) (lobby.Table, error) {
+		before = t
+		return fn(t)
+	})
+	if err != nil {
+		return lobby.Table{}, err
+	}
+	announce(before, t)
+	rm.broadcast(func(player string) any {
+		return map[string]any{"type": "snapshot", "table": view(t, player)}
+	})
+	rm.arm(t.State)
+	return t, nil
+}
+
+// authorize checks player's seat token, telling a player who never sat
+// apart from one who gave the wrong token.
+func authorize(t lobby.Table, player, token string) error {
+	if seatOf(t.State, player) < 0 {
+		return &game.InvalidActionError{Msg: fmt.Sprintf("%s is not seated", player)}
+	}
+	return t.Authorize(player, token)
+}
+
+// sit seats player with buyIn chips. When c, the client asking, is given,
+// it is sent the player's new seat token and holds it from then on.
+func (rm *room) sit(c *client, seat int, player string, buyIn int64) (lobby.Table, error) {
+	return rm.update(func(t lobby.Table) (lobby.Table, error) {
+		return t.Sit(seat, player, buyIn)
+	}, func(_, next lobby.Table) {
+		rm.broadcast(func(string) any {
+			return map[string]any{"type": "sit", "seat": seat, "player": player, "stack": buyIn}
+		})
+		if c != nil {
+			c.token = next.Tokens[player]
+			c.sendJSON(map[string]any{"type": "token", "seat": seat, "token": c.token})
+		}
+	})
+}
+
+// stand unseats player, returning the chips they leave with. Clients
+// holding the player's token lose it.
+func (rm *room) stand(player, token string) (int64, error) {
+	var seat int
+	var stack int64
+	_, err := rm.update(func(t lobby.Table) (lobby.Table, error) {
+		if err := authorize(t, player, token); err != nil {
+			return lobby.Table{}, err
+		}
+		seat = seatOf(t.State, player)
+		var err error
+		t, stack, err = t.Stand(seat)
+		return t, err
+	}, func(_, _ lobby.Table) {
+		for c := range rm.clients {
+			if c.player == player {
+				c.token = ""
+			}
+		}
+		rm.broadcast(func(string) any {
+			return map[string]any{"type": "stand", "seat": seat, "player": player, "stack": stack}
+		})
+	})
+	return stack, err
+}
+
+// seed sets the client seed player adds to later shuffles.
+func (rm *room) seed(player, token, seed string) error {
+	_, err := rm.update(func(t lobby.Table) (lobby.Table, error) {
+		if err := authorize(t, player, token); err != nil {
+			return lobby.Table{}, err
+		}
+		return t.SetClientSeed(player, seed)
+	}, func(_, _ lobby.Table) {})
+	return err
+}
+
+// start deals the next hand at player's request.
+func (rm *room) start(player, token string) error {
+	_, err := rm.update(func(t lobby.Table) (lobby.Table, error) {
+		if err := authorize(t, player, token); err != nil {
+			return lobby.Table{}, err
+		}
+		return t.Deal()
+	}, func(before, next lobby.Table) {
+		rm.broadcast(func(player string) any {
+			s := next.State
+			ev := map[string]any{
+				"type":         "deal",
+				"hand_number":  s.HandNumber,
+				"button":       s.Button,
+				"commitment":   next.Shuffle.Commitment,
+				"client_seeds": next.Shuffle.ClientSeeds,
+			}
+			if p := seatOf(s, player); p >= 0 && s.Seats[p].InHand {
+				ev["hole_cards"] = cardsToStrings(s.Seats[p].Hole)
+			}
+			return ev
+		})
+		rm.afterStreet(before, next)
+	})
+	return err
+}
+
+// act plays player's move.
+func (rm *room) act(player, token, action string, amount int64) error {
+	typ, err := game.ParseActionType(action)
+	if err != nil {
+		return err
+	}
+	var seat int
+	_, err = rm.update(func(t lobby.Table) (lobby.Table, error) {
+		if err := authorize(t, player, token); err != nil {
+			return lobby.Table{}, err
+		}
+		seat = seatOf(t.State, player)
+		var err error
+		t.State, err = t.State.Apply(game.Action{Seat: seat, Type: typ, Amount: amount})
+		return t, err
+	}, func(before, next lobby.Table) {
+		rm.announceAction(before, next, seat, typ, false)
+	})
+	return err
+}
+
+// arm starts the action timer when a new turn begins in s. Changes that
+// leave the turn as it was, such as a player sitting down, do not restart
+// it.
+func (rm *room) arm(s game.State) {
+	t := turn{hand: s.HandNumber, seat: s.ToAct}
+	if s.History != nil {
+		t.actions = len(s.History.Actions)
+	}
+	if t == rm.turn {
+		return
+	}
+	rm.turn = t
+	if rm.timer != nil {
+		rm.timer.Stop()
+	}
+	if rm.timeout > 0 && t.seat >= 0 {
+		rm.timer = time.AfterFunc(rm.timeout, func() { rm.expire(t) })
+	}
+}
+
+// expire moves for the player whose turn t ran out: a check when they may,
+// or else a fold. It does nothing if the turn was played meanwhile.
+func (rm *room) expire(t turn) {
+	var typ game.ActionType
+	rm.update(func(table lobby.Table) (lobby.Table, error) {
+		s := table.State
+		if s.HandNumber != t.hand || s.ToAct != t.seat || s.History == nil || len(s.History.Actions) != t.actions {
+			return lobby.Table{}, errTurnOver
+		}
+		typ = game.Fold
+		for _, a := range s.LegalActions() {
+			if a.Type == game.Check {
+				typ = game.Check
+			}
+		}
+		var err error
+		table.State, err = s.Apply(game.Action{Seat: t.seat, Type: typ})
+		return table, err
+	}, func(before, next lobby.Table) {
+		rm.announceAction(before, next, t.seat, typ, true)
+	})
+}
+
+// announceAction broadcasts seat's move, and what it dealt or ended.
+func (rm *room) announceAction(before, next lobby.Table, seat int, typ game.ActionType, timedOut bool) {
+	st := next.State.Seats[seat]
+	ev := map[string]any{"type": "action", "seat": seat, "action": typ, "bet": st.Bet, "stack": st.Stack}
+	if timedOut {
+		ev["timed_out"] = true
+	}
+	rm.broadcast(func(string) any { return ev })
+	rm.afterStreet(before, next)
+}
+
+// afterStreet announces new board cards and the end of the hand, whose
+// history it stores and whose server seed it reveals.
+func (rm *room) afterStreet(beforeTable, nextTable lobby.Table) {
+	before, next := beforeTable.State, nextTable.State
+	if len(next.Board) > len(before.Board) {
+		rm.broadcast(func(string) any {
+			return map[string]any{"type": "street", "street": next.Street.String(), "board": cardsToStrings(next.Board)}
+		})
+	}
+	if next.Street == game.Complete && before.Street != game.Complete {
+		hands := []map[string]any{}
+		for seat, h := range next.Result.Hands {
+			if h != nil {
+				hands = append(hands, shownHand(next, seat, h))
+			}
+		}
+		h := *next.History
+		h.Seats = append([]game.HistorySeat(nil), h.Seats...)
+		for i, st := range h.Seats {
+			h.Seats[i].Token = nextTable.Tokens[st.Player]
+		}
+		h.ID = fmt.Sprintf("%s-%d", rm.id, h.HandNumber)
+		h.Table = rm.id
+		h.Time = time.Now()
+		h.Shuffle = nextTable.Shuffle
+		if err := rm.store.SaveHand(h); err != nil {
+			log.Printf("table %s: saving hand %d: %v", rm.id, h.HandNumber, err)
+		}
+		rm.broadcast(func(player string) any {
+			ev := map[string]any{"type": "showdown", "hand_id": h.ID, "pots": next.Result.Pots, "won": next.Result.Won, "hands": hands}
+			if h.DealtIn(player) {
+				ev["shuffle"] = h.Shuffle
+			}
+			return ev
+		})
+	}
+}
+
+// broadcast sends each client the message msg builds for the player it
+// may see the table as.
+func (rm *room) broadcast(msg func(player string) any) {
+	for c := range rm.clients {
+		c.sendJSON(msg(c.viewer()))
+	}
+}
+
+// view describes the table as player may see it: hole cards are shown only
+// to their owner, or to everyone once shown down. An empty player sees no
+// hole cards until the showdown. The last hand's server seed is shown only
+// to players dealt into it, once it is over.
+func view(t lobby.Table, player string) map[string]any {
+	s := t.State
+	you := seatOf(s, player)
+	var pot int64
+	seats := make([]map[string]any, len(s.Seats))
+	for i, st := range s.Seats {
+		pot += st.Committed
+		seats[i] = map[string]any{"seat": i}
+		if st.Player == "" {
+			continue
+		}
+		seats[i]["player"] = st.Player
+		seats[i]["stack"] = st.Stack
+		seats[i]["bet"] = st.Bet
+		seats[i]["in_hand"] = st.InHand
+		seats[i]["all_in"] = st.AllIn
+		shown := s.Result != nil && s.Result.Hands[i] != nil
+		if len(st.Hole) > 0 && (i == you || shown) {
+			seats[i]["hole_cards"] = cardsToStrings(st.Hole)
+		}
+	}
+	v := map[string]any{
+		"id":          t.ID,
+		"variant":     s.Config.Variant.String(),
+		"limit":       s.Config.Limit.String(),
+		"small_blind": s.Config.SmallBlind,
+		"big_blind":   s.Config.BigBlind,
+		"max_seats":   s.Config.MaxSeats,
+		"min_buy_in":  t.MinBuyIn,
+		"max_buy_in":  t.MaxBuyIn,
+		"hand_number": s.HandNumber,
+		"street":      s.Street.String(),
+		"button":      s.Button,
+		"to_act":      s.ToAct,
+		"current_bet": s.CurrentBet,
+		"pot":         pot,
+		"board":       cardsToStrings(s.Board),
+		"seats":       seats,
+		"you":         you,
+	}
+	if you >= 0 && you == s.ToAct {
+		v["legal_actions"] = s.LegalActions()
+	}
+	v["next_commitment"] = deck.Commit(t.NextSeed)
+	if t.Shuffle != nil {
+		shuffle := *t.Shuffle
+		if s.InHand() || s.History == nil || !s.History.DealtIn(player) {
+			shuffle.ServerSeed = ""
+		}
+		v["shuffle"] = shuffle
+	}
+	if player != "" {
+		v["client_seed"] = t.ClientSeeds[player]
+	}
+	return v
+}
+
+func shownHand(s game.State, seat int, h *poker.EvaluatedHand) map[string]any {
+	shown := map[string]any{
+		"seat":       seat,
+		"hole_cards": cardsToStrings(s.Seats[seat].Hole),
+		"best_hand":  cardsToStrings(h.BestHand),
+		"rank_name":  h.RankName,
+	}
+	if s.Config.Variant.HiLo() {
+		shown["low"] = lowToJSON(h.Low)
+	}
+	return shown
+}
+
+// seatOf returns player's seat, or -1.
+func seatOf(s game.State, player string) int {
+	for i, st := range s.Seats {
+		if player != "" && st.Player == player {
+			return i
+		}
+	}
+	return -1
+}
+
+// sendJSON queues msg for the client, dropping a client too slow to keep up.
+func (c *client) sendJSON(msg any) {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	select {
+	case c.send <- b:
+	default:
+		c.conn.Close()
+	}
+}
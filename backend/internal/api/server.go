@@ -4,25 +4,81 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/texas-holdem/backend/internal/lobby"
 )
 
+// defaultExactThreshold is the largest number of deals /probability
+// enumerates exactly; a heads-up flop (about 1.07M deals) fits.
+const defaultExactThreshold = 2000000
+
+// defaultActionTimeout is how long a live table waits for the player to act
+// before checking or folding for them.
+const defaultActionTimeout = 30 * time.Second
+
 type Server struct {
-	mux          *http.ServeMux
-	allowedOrigin string
+	mux            *http.ServeMux
+	allowedOrigin  string
+	exactThreshold int64
+	actionTimeout  time.Duration
+
+	tables  lobby.Store
+	roomsMu sync.Mutex
+	rooms   map[string]*room
 }
 
+// New returns a server keeping its tables in process memory.
 func New() *Server {
+	return NewWithStore(lobby.NewMemoryStore())
+}
+
+// NewWithStore returns a server keeping its tables in store.
+func NewWithStore(store lobby.Store) *Server {
 	allowedOrigin := os.Getenv("ALLOWED_ORIGIN")
 	if allowedOrigin == "" {
 		allowedOrigin = "http://34.58.122.79"
 	}
+	exactThreshold := int64(defaultExactThreshold)
+	if v := os.Getenv("EXACT_THRESHOLD"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			log.Printf("ignoring invalid EXACT_THRESHOLD %q", v)
+		} else {
+			exactThreshold = n
+		}
+	}
+	actionTimeout := defaultActionTimeout
+	if v := os.Getenv("ACTION_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			log.Printf("ignoring invalid ACTION_TIMEOUT %q", v)
+		} else {
+			actionTimeout = d
+		}
+	}
 	s := &Server{
-		mux:          http.NewServeMux(),
-		allowedOrigin: allowedOrigin,
+		mux:            http.NewServeMux(),
+		allowedOrigin:  allowedOrigin,
+		exactThreshold: exactThreshold,
+		actionTimeout:  actionTimeout,
+		tables:         store,
+		rooms:          make(map[string]*room),
 	}
 	s.mux.HandleFunc("/api/v1/evaluate", s.handleEvaluate)
 	s.mux.HandleFunc("/api/v1/compare", s.handleCompare)
 	s.mux.HandleFunc("/api/v1/probability", s.handleProbability)
+	s.mux.HandleFunc("/api/v1/showdown", s.handleShowdown)
+	s.mux.HandleFunc("/api/v1/equity", s.handleEquity)
+	s.mux.HandleFunc("/api/v1/outs", s.handleOuts)
+	s.mux.HandleFunc("/api/v1/tables", s.handleTables)
+	s.mux.HandleFunc("/api/v1/tables/", s.handleTable)
+	s.mux.HandleFunc("/api/v1/hands/", s.handleHand)
+	s.mux.HandleFunc("/api/v1/replay", s.handleReplay)
+	s.mux.HandleFunc("/api/v1/verify", s.handleVerify)
+	s.mux.HandleFunc("/api/v1/ws", s.handleWebSocket)
 	s.mux.HandleFunc("/health", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte("ok"))
@@ -0,0 +1,202 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/texas-holdem/backend/internal/game"
+	"github.com/texas-holdem/backend/internal/lobby"
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// handleTables lists the open tables (GET) or creates one (POST).
+func (s *Server) handleTables(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		tables, err := s.tables.List()
+		if err != nil {
+			respondTableError(w, err)
+			return
+		}
+		open := []map[string]any{}
+		for _, t := range tables {
+			if t.Open() {
+				open = append(open, tableSummary(t))
+			}
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"tables": open})
+	case http.MethodPost:
+		var req struct {
+			Variant    string `json:"variant"`
+			Limit      string `json:"limit"` // "no_limit" (default), "pot_limit" or "fixed_limit"
+			SmallBlind int64  `json:"small_blind"`
+			BigBlind   int64  `json:"big_blind"`
+			MaxSeats   int    `json:"max_seats"`
+			MinBuyIn   int64  `json:"min_buy_in"` // both 0: 20 to 100 big blinds
+			MaxBuyIn   int64  `json:"max_buy_in"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		cfg := game.Config{SmallBlind: req.SmallBlind, BigBlind: req.BigBlind, MaxSeats: req.MaxSeats}
+		var err error
+		if cfg.Variant, err = poker.ParseVariant(req.Variant); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if cfg.Limit, err = game.ParseLimit(req.Limit); err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		t, err := lobby.NewTable(cfg, req.MinBuyIn, req.MaxBuyIn)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if t, err = s.tables.Create(t); err != nil {
+			respondTableError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusCreated, tableSummary(t))
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleTable serves /api/v1/tables/{id}: GET shows the table without any
+// hole cards; POST to .../sit and .../stand seats and unseats players. Sit
+// answers with the player's seat token, which stand requires.
+func (s *Server) handleTable(w http.ResponseWriter, r *http.Request) {
+	id, op, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/v1/tables/"), "/")
+	switch {
+	case op == "" && r.Method == http.MethodGet:
+		t, err := s.tables.Get(id)
+		if err != nil {
+			respondTableError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, view(t, ""))
+	case (op == "sit" || op == "stand") && r.Method == http.MethodPost:
+		var req struct {
+			Player string `json:"player"`
+			Seat   int    `json:"seat"`   // sit
+			BuyIn  int64  `json:"buy_in"` // sit
+			Token  string `json:"token"`  // stand
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "invalid JSON")
+			return
+		}
+		if req.Player == "" {
+			respondError(w, http.StatusBadRequest, "need a player")
+			return
+		}
+		rm, err := s.room(id)
+		if err != nil {
+			respondTableError(w, err)
+			return
+		}
+		if op == "sit" {
+			t, err := rm.sit(nil, req.Seat, req.Player, req.BuyIn)
+			if err != nil {
+				respondTableError(w, err)
+				return
+			}
+			v := view(t, req.Player)
+			v["token"] = t.Tokens[req.Player]
+			respondJSON(w, http.StatusOK, v)
+			return
+		}
+		stack, err := rm.stand(req.Player, req.Token)
+		if err != nil {
+			respondTableError(w, err)
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{"stack": stack})
+	case op == "" || op == "sit" || op == "stand":
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// tableSummary describes a table for the lobby.
+func tableSummary(t lobby.Table) map[string]any {
+	cfg := t.State.Config
+	return map[string]any{
+		"id":          t.ID,
+		"variant":     cfg.Variant.String(),
+		"limit":       cfg.Limit.String(),
+		"small_blind": cfg.SmallBlind,
+		"big_blind":   cfg.BigBlind,
+		"max_seats":   cfg.MaxSeats,
+		"seated":      t.Seated(),
+		"min_buy_in":  t.MinBuyIn,
+		"max_buy_in":  t.MaxBuyIn,
+		"in_hand":     t.State.InHand(),
+	}
+}
+
+// respondTableError maps a table error to its status: 404 for an unknown
+// table or hand, 403 for a wrong seat token, 409 for a move the table's
+// state does not allow.
+func respondTableError(w http.ResponseWriter, err error) {
+	switch {
+	case lobby.IsNotFound(err):
+		respondError(w, http.StatusNotFound, err.Error())
+	case lobby.IsUnauthorized(err):
+		respondError(w, http.StatusForbidden, err.Error())
+	case poker.IsInvalidInput(err):
+		respondError(w, http.StatusBadRequest, err.Error())
+	case game.IsInvalidAction(err):
+		respondError(w, http.StatusConflict, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// handleHand serves /api/v1/hands/{id}: a finished hand's history as
+// ?player= may see it, given their seat token as ?token=, as JSON or, with
+// ?format=pokerstars, as PokerStars text.
+func (s *Server) handleHand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	h, err := s.tables.Hand(strings.TrimPrefix(r.URL.Path, "/api/v1/hands/"))
+	if err != nil {
+		respondTableError(w, err)
+		return
+	}
+	player := r.URL.Query().Get("player")
+	if h, err = handFor(h, player, r.URL.Query().Get("token")); err != nil {
+		respondTableError(w, err)
+		return
+	}
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		respondJSON(w, http.StatusOK, h)
+	case "pokerstars":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(h.PokerStars(player)))
+	default:
+		respondError(w, http.StatusBadRequest, "format must be json or pokerstars")
+	}
+}
+
+// handFor returns h as player may see it, once token shows they are the
+// player who was dealt in. An empty player needs no token.
+func handFor(h game.HandHistory, player, token string) (game.HandHistory, error) {
+	if player == "" {
+		return h.For(""), nil
+	}
+	for _, st := range h.Seats {
+		if st.Player == player && st.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(st.Token)) == 1 {
+			return h.For(player), nil
+		}
+	}
+	return game.HandHistory{}, &lobby.UnauthorizedError{Player: player}
+}
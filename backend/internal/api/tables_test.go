@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func getJSON(t *testing.T, s *Server, path string) (int, map[string]any) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var resp map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("%s: decoding %q: %v", path, rec.Body.String(), err)
+	}
+	return rec.Code, resp
+}
+
+func TestTables_Lobby(t *testing.T) {
+	s := New()
+	code, created := postJSON(t, s, "/api/v1/tables", `{"variant": "plo4", "limit": "pot_limit", "small_blind": 1, "big_blind": 2, "max_seats": 2}`)
+	if code != http.StatusCreated || created["id"] != "t1" || created["min_buy_in"] != 40.0 || created["limit"] != "pot_limit" {
+		t.Fatalf("create: %d %v", code, created)
+	}
+
+	code, resp := postJSON(t, s, "/api/v1/tables/t1/sit", `{"player": "alice", "seat": 0, "buy_in": 100}`)
+	if code != http.StatusOK || resp["you"] != 0.0 || resp["token"] == "" {
+		t.Fatalf("sit: %d %v", code, resp)
+	}
+	token := resp["token"].(string)
+	for body, want := range map[string]int{
+		`{"player": "bob", "seat": 0, "buy_in": 100}`:  http.StatusConflict,   // taken
+		`{"player": "bob", "seat": 1, "buy_in": 1000}`: http.StatusBadRequest, // over the max buy-in
+		`{"player": "bob", "seat": 9, "buy_in": 100}`:  http.StatusBadRequest,
+	} {
+		if code, resp := postJSON(t, s, "/api/v1/tables/t1/sit", body); code != want {
+			t.Errorf("sit %s: %d %v, want %d", body, code, resp, want)
+		}
+	}
+	if code, _ := postJSON(t, s, "/api/v1/tables/t9/sit", `{"player": "bob", "seat": 1, "buy_in": 100}`); code != http.StatusNotFound {
+		t.Errorf("sit at an unknown table: %d, want 404", code)
+	}
+
+	_, list := getJSON(t, s, "/api/v1/tables")
+	if tables := list["tables"].([]any); len(tables) != 1 || tables[0].(map[string]any)["seated"] != 1.0 {
+		t.Errorf("list = %v, want t1 with 1 seated", list)
+	}
+	postJSON(t, s, "/api/v1/tables/t1/sit", `{"player": "bob", "seat": 1, "buy_in": 100}`)
+	if _, list = getJSON(t, s, "/api/v1/tables"); len(list["tables"].([]any)) != 0 {
+		t.Errorf("full table still listed: %v", list)
+	}
+
+	if code, _ := postJSON(t, s, "/api/v1/tables/t1/stand", `{"player": "alice"}`); code != http.StatusForbidden {
+		t.Errorf("stand without a token: %d, want 403", code)
+	}
+	code, resp = postJSON(t, s, "/api/v1/tables/t1/stand", `{"player": "alice", "token": "`+token+`"}`)
+	if code != http.StatusOK || resp["stack"] != 100.0 {
+		t.Errorf("stand: %d %v", code, resp)
+	}
+	if code, table := getJSON(t, s, "/api/v1/tables/t1"); code != http.StatusOK || table["seats"].([]any)[0].(map[string]any)["player"] != nil {
+		t.Errorf("get after stand: %d %v", code, table)
+	}
+	if code, _ := postJSON(t, s, "/api/v1/tables/t1/stand", `{"player": "alice", "token": "`+token+`"}`); code != http.StatusConflict {
+		t.Errorf("standing twice: %d, want 409", code)
+	}
+}
+
+func TestTables_CreateInvalid(t *testing.T) {
+	s := New()
+	for _, body := range []string{
+		`{"small_blind": 1, "big_blind": 2, "max_seats": 11}`,
+		`{"small_blind": 2, "big_blind": 1, "max_seats": 6}`,
+		`{"small_blind": 1, "big_blind": 2, "max_seats": 6, "variant": "razz"}`,
+		`{"small_blind": 1, "big_blind": 2, "max_seats": 6, "limit": "spread"}`,
+		`{"small_blind": 1, "big_blind": 2, "max_seats": 6, "min_buy_in": 100, "max_buy_in": 50}`,
+	} {
+		if code, resp := postJSON(t, s, "/api/v1/tables", body); code != http.StatusBadRequest {
+			t.Errorf("%s: %d %v, want 400", body, code, resp)
+		}
+	}
+}
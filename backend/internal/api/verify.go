@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/texas-holdem/backend/internal/deck"
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// handleVerify recomputes a shuffled deck from its revealed seeds, given by
+// hand_id or directly, and checks the server seed against its commitment.
+// For a stored hand it also checks that the deck deals the hand's cards; the
+// deck holds cards nobody showed, so only a player dealt in, proven by their
+// seat token, may verify by hand_id.
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		HandID  string `json:"hand_id"`
+		Player  string `json:"player"` // with hand_id
+		Token   string `json:"token"`  // with hand_id: the player's seat token
+		Variant string `json:"variant"`
+		deck.Proof
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "invalid JSON")
+		return
+	}
+
+	if req.HandID != "" {
+		h, err := s.tables.Hand(req.HandID)
+		if err != nil {
+			respondTableError(w, err)
+			return
+		}
+		if req.Player == "" {
+			respondError(w, http.StatusForbidden, "verifying a stored hand needs the player and token of someone dealt in")
+			return
+		}
+		if _, err := handFor(h, req.Player, req.Token); err != nil {
+			respondTableError(w, err)
+			return
+		}
+		if h.Shuffle == nil {
+			respondError(w, http.StatusBadRequest, "hand was not dealt from a verifiable shuffle")
+			return
+		}
+		cards := h.Shuffle.Deck(h.Config.Variant)
+		deals, err := h.DealtFrom(cards)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondJSON(w, http.StatusOK, map[string]any{
+			"valid":      h.Shuffle.Valid(),
+			"shuffle":    h.Shuffle,
+			"deck":       cardsToStrings(cards),
+			"deals_hand": deals,
+		})
+		return
+	}
+
+	variant, err := poker.ParseVariant(req.Variant)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.ServerSeed == "" {
+		respondError(w, http.StatusBadRequest, "need hand_id or server_seed")
+		return
+	}
+	respondJSON(w, http.StatusOK, map[string]any{
+		"valid":   req.Proof.Valid(),
+		"shuffle": req.Proof,
+		"deck":    cardsToStrings(req.Proof.Deck(variant)),
+	})
+}
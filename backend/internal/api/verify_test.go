@@ -0,0 +1,65 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/texas-holdem/backend/internal/deck"
+	"github.com/texas-holdem/backend/internal/game"
+	"github.com/texas-holdem/backend/internal/lobby"
+)
+
+func TestHandleVerify(t *testing.T) {
+	s := New()
+	table, _ := lobby.NewTable(game.Config{SmallBlind: 1, BigBlind: 2, MaxSeats: 2}, 0, 0)
+	table, _ = table.Sit(0, "alice", 100)
+	table, _ = table.Sit(1, "bob", 100)
+	table, err := table.Deal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.State, err = table.State.Apply(game.Action{Seat: 0, Type: game.Fold}); err != nil {
+		t.Fatal(err)
+	}
+	h := *table.State.History
+	h.ID, h.Shuffle = "t1-1", table.Shuffle
+	h.Seats[1].Token = "bobs-token"
+	s.tables.SaveHand(h)
+
+	code, resp := postJSON(t, s, "/api/v1/verify", `{"hand_id": "t1-1", "player": "bob", "token": "bobs-token"}`)
+	if code != http.StatusOK || resp["valid"] != true || resp["deals_hand"] != true {
+		t.Fatalf("status %d: %v", code, resp)
+	}
+	if len(resp["deck"].([]any)) != 52 {
+		t.Errorf("deck = %v", resp["deck"])
+	}
+
+	// The same seeds given directly give the same deck.
+	p := table.Shuffle
+	body := `{"commitment": "` + p.Commitment + `", "server_seed": "` + p.ServerSeed + `", "client_seeds": ["", ""], "nonce": 1}`
+	code, direct := postJSON(t, s, "/api/v1/verify", body)
+	if code != http.StatusOK || direct["valid"] != true || !reflect.DeepEqual(direct["deck"], resp["deck"]) {
+		t.Errorf("status %d: %v", code, direct)
+	}
+	code, wrong := postJSON(t, s, "/api/v1/verify", `{"commitment": "`+deck.Commit("other")+`", "server_seed": "`+p.ServerSeed+`", "nonce": 1}`)
+	if code != http.StatusOK || wrong["valid"] != false {
+		t.Errorf("wrong commitment: %d %v", code, wrong)
+	}
+
+	h.ID, h.Shuffle = "t1-2", nil
+	s.tables.SaveHand(h)
+	for body, want := range map[string]int{
+		`{"hand_id": "t1-1"}`: http.StatusForbidden,
+		`{"hand_id": "t1-1", "player": "bob", "token": "guess"}`:      http.StatusForbidden,
+		`{"hand_id": "t1-1", "player": "carol", "token": ""}`:         http.StatusForbidden,
+		`{"hand_id": "t1-2", "player": "bob", "token": "bobs-token"}`: http.StatusBadRequest,
+		`{"hand_id": "t1-3"}`:                   http.StatusNotFound,
+		`{"commitment": "ab"}`:                  http.StatusBadRequest,
+		`{"variant": "x", "server_seed": "ab"}`: http.StatusBadRequest,
+	} {
+		if code, resp := postJSON(t, s, "/api/v1/verify", body); code != want {
+			t.Errorf("%s: %d %v, want %d", body, code, resp, want)
+		}
+	}
+}
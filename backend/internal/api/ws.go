@@ -0,0 +1,130 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// WebSocket play at /api/v1/ws. Every message is a JSON object with a
+// "type"; docs/websocket.md describes the protocol in full.
+//
+// Client to server:
+//
+//	{"type": "join", "table": "t1", "player": "alice"}   watch a table, as alice
+//	{"type": "join", "table": "t1", "player": "alice", "token": "…"}   return to alice's seat
+//	{"type": "sit", "seat": 2, "buy_in": 200}
+//	{"type": "stand"}
+//	{"type": "start"}                                   deal the next hand
+//	{"type": "action", "action": "raise", "amount": 40}
+//
+// Server to client: "snapshot" (the whole table as this player may see it),
+// the events "sit", "stand", "deal", "action", "street" and "showdown",
+// "token" with the seat token after this client sits, and "error" for a
+// message that was rejected. A change is sent as its events
+// followed by a fresh snapshot.
+
+// wsSendBuffer is how many messages may queue for a client before it is
+// dropped as too slow.
+const wsSendBuffer = 64
+
+// inbound is a message from a client.
+type inbound struct {
+	Type   string `json:"type"`
+	Table  string `json:"table"`  // join
+	Player string `json:"player"` // join
+	Token  string `json:"token"`  // join: the seat token of a seated player
+	Seat   int    `json:"seat"`   // sit
+	BuyIn  int64  `json:"buy_in"` // sit
+	Action string `json:"action"` // action: fold, check, call, bet, raise or all_in
+	Amount int64  `json:"amount"` // action: the total bet after a bet or raise
+	Seed   string `json:"seed"`   // seed: your client seed for later shuffles
+}
+
+// client is one WebSocket connection. Its fields are guarded by the lock
+// of the room it joined.
+type client struct {
+	conn   *websocket.Conn
+	send   chan []byte
+	player string
+	token  string // the player's seat token, once they have proved it
+}
+
+// viewer is who c may see the table as: its player once it holds their
+// seat token, and nobody before.
+func (c *client) viewer() string {
+	if c.token == "" {
+		return ""
+	}
+	return c.player
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			return origin == "" || origin == s.allowedOrigin
+		},
+	}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // Upgrade has already replied
+	}
+	c := &client{conn: conn, send: make(chan []byte, wsSendBuffer)}
+	go c.writeLoop()
+	defer close(c.send)
+
+	conn.SetReadLimit(4096)
+	var rm *room
+	defer func() {
+		if rm != nil {
+			rm.leave(c)
+		}
+	}()
+	for {
+		_, b, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var msg inbound
+		if err := json.Unmarshal(b, &msg); err != nil {
+			c.sendJSON(map[string]string{"type": "error", "error": "invalid JSON"})
+			continue
+		}
+		switch {
+		case msg.Type == "join" && rm != nil:
+			err = &poker.InvalidInputError{Msg: "already joined a table"}
+		case msg.Type == "join":
+			if msg.Table == "" || msg.Player == "" {
+				err = &poker.InvalidInputError{Msg: "need a table and a player"}
+				break
+			}
+			var joined *room
+			if joined, err = s.room(msg.Table); err == nil {
+				if err = joined.join(c, msg.Player, msg.Token); err == nil {
+					rm = joined
+				}
+			}
+		case rm == nil:
+			err = &poker.InvalidInputError{Msg: "join a table first"}
+		default:
+			err = rm.handle(c, msg)
+		}
+		if err != nil {
+			c.sendJSON(map[string]string{"type": "error", "error": err.Error()})
+		}
+	}
+}
+
+// writeLoop writes queued messages until the queue is closed.
+func (c *client) writeLoop() {
+	defer c.conn.Close()
+	for b := range c.send {
+		if err := c.conn.WriteMessage(websocket.TextMessage, b); err != nil {
+			return
+		}
+	}
+	c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type wsClient struct {
+	t    *testing.T
+	conn *websocket.Conn
+}
+
+// dialTable joins table as player, who must give their seat token once
+// seated.
+func dialTable(t *testing.T, srv *httptest.Server, table, player, token string) *wsClient {
+	t.Helper()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	c := &wsClient{t: t, conn: conn}
+	c.send(map[string]any{"type": "join", "table": table, "player": player, "token": token})
+	c.next("snapshot")
+	return c
+}
+
+func (c *wsClient) send(msg map[string]any) {
+	c.t.Helper()
+	if err := c.conn.WriteJSON(msg); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// next skips messages until one of type typ arrives.
+func (c *wsClient) next(typ string) map[string]any {
+	c.t.Helper()
+	c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var msg map[string]any
+		if err := c.conn.ReadJSON(&msg); err != nil {
+			c.t.Fatalf("waiting for %s: %v", typ, err)
+		}
+		if msg["type"] == typ {
+			return msg
+		}
+		if msg["type"] == "error" {
+			c.t.Fatalf("waiting for %s: %v", typ, msg["error"])
+		}
+	}
+}
+
+func TestWebSocket_PlayHand(t *testing.T) {
+	s := New()
+	postJSON(t, s, "/api/v1/tables", `{"small_blind": 1, "big_blind": 2, "max_seats": 6}`)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	alice := dialTable(t, srv, "t1", "alice", "")
+	bob := dialTable(t, srv, "t1", "bob", "")
+	carol := dialTable(t, srv, "t1", "carol", "") // watches without a seat
+
+	alice.send(map[string]any{"type": "sit", "seat": 0, "buy_in": 100})
+	alice.next("token")
+	bob.send(map[string]any{"type": "sit", "seat": 1, "buy_in": 100})
+	bobToken := bob.next("token")["token"].(string)
+	alice.next("sit")
+	alice.next("snapshot") // after bob sat
+	alice.send(map[string]any{"type": "seed", "seed": "alice's luck"})
+	table := alice.next("snapshot")["table"].(map[string]any)
+	if table["client_seed"] != "alice's luck" {
+		t.Errorf("client seed = %v", table["client_seed"])
+	}
+	commitment := table["next_commitment"]
+	alice.send(map[string]any{"type": "start"})
+
+	for i, c := range []*wsClient{alice, bob} {
+		deal := c.next("deal")
+		if cards, _ := deal["hole_cards"].([]any); len(cards) != 2 {
+			t.Errorf("player %d dealt %v", i, deal["hole_cards"])
+		}
+		if deal["commitment"] != commitment || deal["client_seeds"].([]any)[0] != "alice's luck" {
+			t.Errorf("deal = %v, want commitment %v", deal, commitment)
+		}
+		table := c.next("snapshot")["table"].(map[string]any)
+		if shuffle := table["shuffle"].(map[string]any); shuffle["server_seed"] != nil || shuffle["commitment"] != commitment {
+			t.Errorf("shuffle during the hand: %v", shuffle)
+		}
+		seats := table["seats"].([]any)
+		if _, ok := seats[i].(map[string]any)["hole_cards"]; !ok {
+			t.Errorf("player %d cannot see its own cards", i)
+		}
+		if _, ok := seats[1-i].(map[string]any)["hole_cards"]; ok {
+			t.Errorf("player %d sees the other player's cards", i)
+		}
+	}
+
+	// Heads-up the button, seat 0 on the first hand, acts first.
+	bob.send(map[string]any{"type": "action", "action": "fold"})
+	if msg := bob.next("error"); msg["error"] == "" {
+		t.Error("acting out of turn was not rejected")
+	}
+	alice.send(map[string]any{"type": "action", "action": "fold"})
+	if ev := bob.next("action"); ev["action"] != "fold" || ev["seat"] != 0.0 {
+		t.Errorf("action event = %v", ev)
+	}
+	showdown := bob.next("showdown")
+	if won := showdown["won"].([]any); won[1] != 2.0 {
+		t.Errorf("won = %v, want bob to take the 2-chip pot", won)
+	}
+
+	if shuffle := showdown["shuffle"].(map[string]any); shuffle["server_seed"] == nil || shuffle["commitment"] != commitment {
+		t.Errorf("showdown shuffle = %v", shuffle)
+	}
+	if ev := carol.next("showdown"); ev["shuffle"] != nil {
+		t.Errorf("spectator's showdown shuffle = %v", ev["shuffle"])
+	}
+	if shuffle := carol.next("snapshot")["table"].(map[string]any)["shuffle"].(map[string]any); shuffle["server_seed"] != nil {
+		t.Errorf("spectator's snapshot shuffle = %v", shuffle)
+	}
+
+	if showdown["hand_id"] != "t1-1" {
+		t.Fatalf("hand_id = %v", showdown["hand_id"])
+	}
+	if code, _ := getJSON(t, s, "/api/v1/hands/t1-1?player=bob"); code != http.StatusForbidden {
+		t.Errorf("history as bob without his token: %d, want 403", code)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/hands/t1-1?format=pokerstars&player=bob&token="+bobToken, nil))
+	text := rec.Body.String()
+	if rec.Code != http.StatusOK || !strings.HasPrefix(text, "PokerStars Hand #1: ") || !strings.Contains(text, "Dealt to bob [") || strings.Contains(text, "Dealt to alice") || !strings.Contains(text, "alice: folds\n") {
+		t.Errorf("history: %d\n%s", rec.Code, text)
+	}
+	_, hist := getJSON(t, s, "/api/v1/hands/t1-1")
+	for _, seat := range hist["seats"].([]any) {
+		if _, ok := seat.(map[string]any)["hole_cards"]; ok {
+			t.Errorf("public history shows %v", seat)
+		}
+	}
+	if hist["shuffle"] != nil {
+		t.Errorf("public history reveals the shuffle: %v", hist["shuffle"])
+	}
+	if code, _ := getJSON(t, s, "/api/v1/hands/t1-2"); code != http.StatusNotFound {
+		t.Errorf("unknown hand: %d, want 404", code)
+	}
+}
+
+func TestWebSocket_Errors(t *testing.T) {
+	s := New()
+	postJSON(t, s, "/api/v1/tables", `{"small_blind": 1, "big_blind": 2, "max_seats": 6}`)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	dialTable(t, srv, "t1", "alice", "")
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	c := &wsClient{t: t, conn: conn}
+	for _, msg := range []map[string]any{
+		{"type": "start"},
+		{"type": "join", "table": "t1"},
+		{"type": "join", "table": "t1", "player": "alice"},
+		{"type": "join", "table": "t2", "player": "bob"},
+	} {
+		c.send(msg)
+		var reply map[string]any
+		if err := conn.ReadJSON(&reply); err != nil || reply["type"] != "error" {
+			t.Errorf("%v: got %v (%v), want an error", msg, reply, err)
+		}
+	}
+}
+
+func TestWebSocket_SeatTokens(t *testing.T) {
+	s := New()
+	postJSON(t, s, "/api/v1/tables", `{"small_blind": 1, "big_blind": 2, "max_seats": 6}`)
+	_, sat := postJSON(t, s, "/api/v1/tables/t1/sit", `{"player": "alice", "seat": 0, "buy_in": 100}`)
+	token, _ := sat["token"].(string)
+	if token == "" {
+		t.Fatalf("sit returned no token: %v", sat)
+	}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/api/v1/ws", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	for _, msg := range []map[string]any{
+		{"type": "join", "table": "t1", "player": "alice"},
+		{"type": "join", "table": "t1", "player": "alice", "token": "guess"},
+	} {
+		conn.WriteJSON(msg)
+		var reply map[string]any
+		if err := conn.ReadJSON(&reply); err != nil || reply["type"] != "error" {
+			t.Errorf("%v: got %v (%v), want an error", msg, reply, err)
+		}
+	}
+
+	alice := dialTable(t, srv, "t1", "alice", token)
+	alice.send(map[string]any{"type": "seed", "seed": "mine"})
+	if table := alice.next("snapshot")["table"].(map[string]any); table["you"] != 0.0 || table["client_seed"] != "mine" {
+		t.Errorf("alice with her token sees %v", table)
+	}
+	if code, _ := postJSON(t, s, "/api/v1/tables/t1/stand", `{"player": "alice", "token": "guess"}`); code != http.StatusForbidden {
+		t.Errorf("stand with the wrong token: %d, want 403", code)
+	}
+}
+
+func TestWebSocket_ActionTimer(t *testing.T) {
+	s := New()
+	s.actionTimeout = 20 * time.Millisecond
+	postJSON(t, s, "/api/v1/tables", `{"small_blind": 1, "big_blind": 2, "max_seats": 6}`)
+	_, sat := postJSON(t, s, "/api/v1/tables/t1/sit", `{"player": "alice", "seat": 0, "buy_in": 100}`)
+	postJSON(t, s, "/api/v1/tables/t1/sit", `{"player": "bob", "seat": 1, "buy_in": 100}`)
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	alice := dialTable(t, srv, "t1", "alice", sat["token"].(string))
+	alice.send(map[string]any{"type": "start"})
+
+	// Alice faces the big blind on the button, so the timer folds for her.
+	if ev := alice.next("action"); ev["seat"] != 0.0 || ev["action"] != "fold" || ev["timed_out"] != true {
+		t.Errorf("action event = %v, want a timed-out fold", ev)
+	}
+	if won := alice.next("showdown")["won"].([]any); won[1] != 2.0 {
+		t.Errorf("won = %v", won)
+	}
+}
@@ -0,0 +1,149 @@
+// Package deck shuffles game decks provably fairly. The server commits to a
+// secret seed by publishing its SHA-256 hash before a hand; the deck order
+// is drawn from an HMAC-DRBG seeded with the server seed, the players'
+// client seeds and the hand number; after the hand the server seed is
+// revealed to its players, who can check the commitment and recompute the
+// deck.
+package deck
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// Proof is what a player needs to audit one shuffle.
+type Proof struct {
+	Commitment  string   `json:"commitment"`            // hex SHA-256 of ServerSeed, published before the hand
+	ServerSeed  string   `json:"server_seed,omitempty"` // revealed once the hand is over
+	ClientSeeds []string `json:"client_seeds"`          // one per player dealt in, in seat order
+	Nonce       int      `json:"nonce"`                 // the hand number
+}
+
+// NewServerSeed returns a fresh secret seed: 32 random bytes in hex.
+func NewServerSeed() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
+
+// Commit returns the commitment to serverSeed: the hex SHA-256 of its text.
+func Commit(serverSeed string) string {
+	sum := sha256.Sum256([]byte(serverSeed))
+	return hex.EncodeToString(sum[:])
+}
+
+// Order returns variant's deck shuffled by the seeds: the cards in index
+// order (hearts, diamonds, clubs, spades, each from the 2 up), shuffled by
+// poker.Deck's Fisher-Yates with draws from NewDRBG.
+func Order(variant poker.Variant, serverSeed string, clientSeeds []string, nonce int) []poker.Card {
+	d := poker.NewDeck(variant)
+	d.Shuffle(NewDRBG(serverSeed, clientSeeds, nonce))
+	cards, _ := d.Peek(d.Remaining())
+	return cards
+}
+
+// Valid reports whether p's server seed matches its commitment.
+func (p Proof) Valid() bool {
+	return p.ServerSeed != "" && hmac.Equal([]byte(Commit(p.ServerSeed)), []byte(p.Commitment))
+}
+
+// Deck returns the deck p's seeds shuffle for variant.
+func (p Proof) Deck(variant poker.Variant) []poker.Card {
+	return Order(variant, p.ServerSeed, p.ClientSeeds, p.Nonce)
+}
+
+// DRBG is HMAC_DRBG with SHA-256 (NIST SP 800-90A) without reseeding.
+type DRBG struct {
+	k, v []byte
+	buf  []byte // generated bytes not yet used
+}
+
+// NewDRBG seeds a DRBG with the server seed, each client seed and the nonce,
+// each written as a 4-byte big-endian length and its bytes; the nonce is
+// written as 8 big-endian bytes.
+func NewDRBG(serverSeed string, clientSeeds []string, nonce int) *DRBG {
+	var seed []byte
+	for _, s := range append([]string{serverSeed}, clientSeeds...) {
+		seed = binary.BigEndian.AppendUint32(seed, uint32(len(s)))
+		seed = append(seed, s...)
+	}
+	seed = binary.BigEndian.AppendUint64(seed, uint64(nonce))
+	return newDRBG(seed)
+}
+
+// newDRBG instantiates the DRBG with seed as its entropy input.
+func newDRBG(seed []byte) *DRBG {
+	d := &DRBG{k: make([]byte, sha256.Size), v: make([]byte, sha256.Size)}
+	for i := range d.v {
+		d.v[i] = 0x01
+	}
+	d.update(seed)
+	return d
+}
+
+func (d *DRBG) hmac(key []byte, parts ...[]byte) []byte {
+	h := hmac.New(sha256.New, key)
+	for _, p := range parts {
+		h.Write(p)
+	}
+	return h.Sum(nil)
+}
+
+func (d *DRBG) update(data []byte) {
+	d.k = d.hmac(d.k, d.v, []byte{0x00}, data)
+	d.v = d.hmac(d.k, d.v)
+	if len(data) > 0 {
+		d.k = d.hmac(d.k, d.v, []byte{0x01}, data)
+		d.v = d.hmac(d.k, d.v)
+	}
+}
+
+// generate returns n bytes from one generate call.
+func (d *DRBG) generate(n int) []byte {
+	var out []byte
+	for len(out) < n {
+		d.v = d.hmac(d.k, d.v)
+		out = append(out, d.v...)
+	}
+	d.update(nil)
+	return out[:n]
+}
+
+// Read fills p with generated bytes, taken from generate calls of 32 bytes
+// each; bytes p does not use are kept for the next Read.
+func (d *DRBG) Read(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if len(d.buf) == 0 {
+			d.buf = d.generate(sha256.Size)
+		}
+		c := copy(p, d.buf)
+		d.buf, p = d.buf[c:], p[c:]
+	}
+	return n, nil
+}
+
+// Uint64 returns 8 generated bytes, big-endian.
+func (d *DRBG) Uint64() uint64 {
+	var b [8]byte
+	d.Read(b[:])
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// Intn returns a uniform int in [0, n), n > 0, rejecting the top values of
+// Uint64 that would bias it.
+func (d *DRBG) Intn(n int) int {
+	limit := ^uint64(0) - ^uint64(0)%uint64(n)
+	for {
+		if x := d.Uint64(); x < limit {
+			return int(x % uint64(n))
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package deck
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// The first HMAC_DRBG SHA-256 vector of NIST's CAVP set, without
+// personalization string, additional input or prediction resistance.
+func TestDRBG_KnownAnswer(t *testing.T) {
+	entropy, _ := hex.DecodeString("ca851911349384bffe89de1cbdc46e6831e44d34a4fb935ee285dd14b71a7488" + "659ba96c601dc69fc902940805ec0ca8")
+	want := "e528e9abf2dece54d47c7e75e5fe302149f817ea9fb4bee6f4199697d04d5b89d54fbb978a15b5c443c9ec21036d2460b6f73ebad0dc2aba6e624abf07745bc107694bb7547bb0995f70de25d6b29e2d3011bb19d27676c07162c8b5ccde0668961df86803482cb37ed6d5c0bb8d50cf1f50d476aa0458bdaba806f48be9dcb8"
+	d := newDRBG(entropy)
+	d.generate(128)
+	if got := hex.EncodeToString(d.generate(128)); got != want {
+		t.Errorf("returned bits\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	order := Order(poker.Holdem, "server", []string{"alice", ""}, 7)
+	// Pinned so that verifiers written elsewhere can check against it.
+	want := []string{"C6", "C8", "H8", "C9", "CT", "H6", "SA", "D9", "HJ", "SK"}
+	for i, s := range want {
+		if order[i].String() != s {
+			t.Fatalf("order starts %v, want %v", order[:len(want)], want)
+		}
+	}
+	if set := poker.CardSetOf(order); len(order) != 52 || set != poker.Holdem.Deck() {
+		t.Errorf("order is not a permutation of the deck: %v", order)
+	}
+	if short := Order(poker.ShortDeck, "server", nil, 1); poker.CardSetOf(short) != poker.ShortDeck.Deck() || len(short) != 36 {
+		t.Errorf("short deck order: %v", short)
+	}
+
+	for name, other := range map[string][]poker.Card{
+		"client seed": Order(poker.Holdem, "server", []string{"alice", "bob"}, 7),
+		"seed split":  Order(poker.Holdem, "server", []string{"alic", "e"}, 7),
+		"nonce":       Order(poker.Holdem, "server", []string{"alice", ""}, 8),
+		"server seed": Order(poker.Holdem, "server2", []string{"alice", ""}, 7),
+	} {
+		if reflect.DeepEqual(other, order) {
+			t.Errorf("changing the %s kept the order", name)
+		}
+	}
+}
+
+func TestProof(t *testing.T) {
+	seed, err := NewServerSeed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := Proof{Commitment: Commit(seed), ServerSeed: seed, ClientSeeds: []string{"x"}, Nonce: 1}
+	if !p.Valid() {
+		t.Error("proof with its own commitment is not valid")
+	}
+	if !reflect.DeepEqual(p.Deck(poker.Omaha4), Order(poker.Omaha4, seed, []string{"x"}, 1)) {
+		t.Error("Deck differs from Order")
+	}
+	p.ServerSeed = seed[1:] + "0"
+	if p.Valid() {
+		t.Error("another server seed is valid")
+	}
+	if (Proof{Commitment: Commit("")}).Valid() {
+		t.Error("unrevealed seed is valid")
+	}
+}
+
+func TestDRBG_Intn(t *testing.T) {
+	d := NewDRBG("server", nil, 0)
+	var counts [6]int
+	for i := 0; i < 60000; i++ {
+		counts[d.Intn(6)]++
+	}
+	for i, c := range counts {
+		if c < 9500 || c > 10500 {
+			t.Errorf("Intn(6) gave %d %d times in 60000", i, c)
+		}
+	}
+}
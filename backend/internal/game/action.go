@@ -0,0 +1,176 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ActionType is a kind of move in a betting round.
+type ActionType int
+
+const (
+	Fold ActionType = iota + 1
+	Check
+	Call
+	Bet
+	Raise
+	AllIn
+)
+
+var actionNames = [...]string{Fold: "fold", Check: "check", Call: "call", Bet: "bet", Raise: "raise", AllIn: "all_in"}
+
+func (t ActionType) String() string {
+	if t < Fold || t > AllIn {
+		return "unknown"
+	}
+	return actionNames[t]
+}
+
+// ParseActionType looks an action type up by name, e.g. "raise".
+func ParseActionType(name string) (ActionType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for t := Fold; t <= AllIn; t++ {
+		if actionNames[t] == name {
+			return t, nil
+		}
+	}
+	return 0, &InvalidActionError{Msg: fmt.Sprintf("unknown action %q", name)}
+}
+
+func (t ActionType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *ActionType) UnmarshalText(b []byte) error {
+	v, err := ParseActionType(string(b))
+	if err != nil {
+		return err
+	}
+	*t = v
+	return nil
+}
+
+// Action is a move by the seat to act.
+type Action struct {
+	Seat   int        `json:"seat"`
+	Type   ActionType `json:"type"`
+	Amount int64      `json:"amount,omitempty"` // Bet and Raise: the seat's total bet this street afterwards
+}
+
+// LegalAction is a move open to the seat to act. Min and Max bound the
+// seat's total bet this street after a Bet or Raise; for Call and AllIn both
+// give the total the move puts in front of the seat.
+type LegalAction struct {
+	Type ActionType `json:"type"`
+	Min  int64      `json:"min,omitempty"`
+	Max  int64      `json:"max,omitempty"`
+}
+
+// LegalActions lists the moves open to the seat to act, or nil when no one
+// is to act.
+func (s State) LegalActions() []LegalAction {
+	if !s.Street.betting() || s.ToAct < 0 {
+		return nil
+	}
+	st := s.Seats[s.ToAct]
+	allIn := st.Bet + st.Stack
+	actions := []LegalAction{{Type: Fold}}
+	if st.Bet == s.CurrentBet {
+		actions = append(actions, LegalAction{Type: Check})
+	} else {
+		call := min(s.CurrentBet, allIn)
+		actions = append(actions, LegalAction{Type: Call, Min: call, Max: call})
+	}
+
+	// A raise needs an opponent who can still respond, and a seat that has
+	// acted may not re-raise after an all-in too small to reopen the betting.
+	canRaise := false
+	if !st.Acted && s.count(Seat.canAct) > 1 {
+		var minTotal, maxTotal int64
+		minTotal, maxTotal, canRaise = s.structure().Limits(s.round(s.ToAct))
+		typ := Raise
+		if s.CurrentBet == 0 {
+			typ = Bet
+		}
+		if canRaise && allIn >= minTotal {
+			actions = append(actions, LegalAction{Type: typ, Min: minTotal, Max: min(maxTotal, allIn)})
+		}
+		canRaise = canRaise && allIn <= maxTotal
+	}
+	if allIn <= s.CurrentBet || canRaise {
+		actions = append(actions, LegalAction{Type: AllIn, Min: allIn, Max: allIn})
+	}
+	return actions
+}
+
+// Apply plays an action by the seat to act.
+func (s State) Apply(a Action) (State, error) {
+	if !s.Street.betting() || s.ToAct < 0 {
+		return State{}, &InvalidActionError{Msg: "no hand in progress"}
+	}
+	if a.Seat != s.ToAct {
+		return State{}, &InvalidActionError{Msg: fmt.Sprintf("seat %d is to act, not seat %d", s.ToAct, a.Seat)}
+	}
+	var legal *LegalAction
+	for _, la := range s.LegalActions() {
+		if la.Type == a.Type {
+			legal = &la
+			break
+		}
+	}
+	if legal == nil {
+		return State{}, &InvalidActionError{Msg: fmt.Sprintf("seat %d cannot %v now", a.Seat, a.Type)}
+	}
+	if (a.Type == Bet || a.Type == Raise) && (a.Amount < legal.Min || a.Amount > legal.Max) {
+		return State{}, &BetSizeError{Type: a.Type, Amount: a.Amount, Min: legal.Min, Max: legal.Max}
+	}
+
+	s = s.clone()
+	st := &s.Seats[a.Seat]
+	street, currentBet, committed := s.Street, s.CurrentBet, st.Committed
+	switch a.Type {
+	case Fold:
+		st.InHand = false
+	case Check:
+	case Call, AllIn:
+		s.raiseTo(a.Seat, legal.Max)
+	case Bet, Raise:
+		s.raiseTo(a.Seat, a.Amount)
+	}
+	st.Acted = true
+	s.record(func(h *HandHistory) {
+		h.Actions = append(h.Actions, HistoryAction{
+			Street: street,
+			Seat:   a.Seat,
+			Type:   a.Type,
+			Amount: a.Amount,
+			Put:    st.Committed - committed,
+			Bet:    st.Bet,
+			Raise:  max(st.Bet-currentBet, 0),
+			AllIn:  st.AllIn,
+		})
+	})
+	if err := s.afterAction(a.Seat); err != nil {
+		return State{}, err
+	}
+	return s, nil
+}
+
+// raiseTo brings a seat's bet up to total. A raise of at least the minimum
+// reopens the betting for everyone else.
+func (s *State) raiseTo(seat int, total int64) {
+	s.put(seat, total-s.Seats[seat].Bet)
+	if total <= s.CurrentBet {
+		return
+	}
+	if raise := total - s.CurrentBet; raise >= s.MinRaise {
+		s.MinRaise = raise
+		s.Raises++
+		for i := range s.Seats {
+			if i != seat {
+				s.Seats[i].Acted = false
+			}
+		}
+	}
+	s.CurrentBet = total
+}
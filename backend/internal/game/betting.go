@@ -0,0 +1,151 @@
+package game
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// Limit is a betting structure: how much a player may bet or raise.
+type Limit int
+
+const (
+	NoLimit    Limit = iota // any amount from the minimum raise up to all in
+	PotLimit                // at most the pot after calling
+	FixedLimit              // one fixed size per street, at most four bets a street
+)
+
+var limitNames = [...]string{NoLimit: "no_limit", PotLimit: "pot_limit", FixedLimit: "fixed_limit"}
+
+func (l Limit) String() string {
+	if !l.Valid() {
+		return "unknown"
+	}
+	return limitNames[l]
+}
+
+// Valid reports whether l is a known betting structure.
+func (l Limit) Valid() bool {
+	return l >= 0 && int(l) < len(limitNames)
+}
+
+// ParseLimit looks a betting structure up by name; "" is no limit, and
+// "nl", "pl" and "fl" abbreviate the others.
+func ParseLimit(name string) (Limit, error) {
+	switch name = strings.ToLower(strings.TrimSpace(name)); name {
+	case "", "nl":
+		return NoLimit, nil
+	case "pl":
+		return PotLimit, nil
+	case "fl", "limit":
+		return FixedLimit, nil
+	}
+	for l, n := range limitNames {
+		if n == name {
+			return Limit(l), nil
+		}
+	}
+	return 0, &poker.InvalidInputError{Msg: fmt.Sprintf("unknown betting structure %q", name)}
+}
+
+func (l Limit) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+func (l *Limit) UnmarshalText(b []byte) error {
+	v, err := ParseLimit(string(b))
+	if err != nil {
+		return err
+	}
+	*l = v
+	return nil
+}
+
+// Structure returns the sizing rules l stands for.
+func (l Limit) Structure() Structure {
+	switch l {
+	case PotLimit:
+		return potLimit{}
+	case FixedLimit:
+		return fixedLimit{Cap: 4}
+	}
+	return noLimit{}
+}
+
+// Round is what a Structure sees of the betting when the seat to act
+// considers a bet or raise.
+type Round struct {
+	Street     Street
+	BigBlind   int64
+	CurrentBet int64 // the largest bet this street
+	MinRaise   int64 // the size of the last full bet or raise
+	Bet        int64 // the seat's own bet this street
+	Pot        int64 // every chip put in this hand, bets included
+	Raises     int   // full bets and raises this street, the big blind counting as one
+}
+
+// Structure sizes bets and raises. Limits returns the smallest and largest
+// total the seat to act may bet or raise to this street, or ok false when
+// it may not raise at all. The table caps both at the seat's stack, and a
+// seat short of the minimum may still go all in up to max.
+type Structure interface {
+	Limits(r Round) (min, max int64, ok bool)
+}
+
+type noLimit struct{}
+
+func (noLimit) Limits(r Round) (int64, int64, bool) {
+	return r.CurrentBet + r.MinRaise, math.MaxInt64, true
+}
+
+type potLimit struct{}
+
+// Limits allows a raise of up to the pot once the seat has called.
+func (potLimit) Limits(r Round) (int64, int64, bool) {
+	call := r.CurrentBet - r.Bet
+	return r.CurrentBet + r.MinRaise, r.CurrentBet + r.Pot + call, true
+}
+
+// fixedLimit bets the big blind before the turn and twice it from the turn
+// on, with at most Cap bets and raises a street.
+type fixedLimit struct {
+	Cap int
+}
+
+func (l fixedLimit) Limits(r Round) (int64, int64, bool) {
+	if r.Raises >= l.Cap {
+		return 0, 0, false
+	}
+	size := r.BigBlind
+	if r.Street >= Turn {
+		size *= 2
+	}
+	return r.CurrentBet + size, r.CurrentBet + size, true
+}
+
+// structure returns the table's sizing rules: Config.Structure when set,
+// otherwise Config.Limit's.
+func (s State) structure() Structure {
+	if s.Config.Structure != nil {
+		return s.Config.Structure
+	}
+	return s.Config.Limit.Structure()
+}
+
+// round describes the betting to a Structure for seat.
+func (s State) round(seat int) Round {
+	r := Round{
+		Street:     s.Street,
+		BigBlind:   s.Config.BigBlind,
+		CurrentBet: s.CurrentBet,
+		MinRaise:   s.MinRaise,
+		Bet:        s.Seats[seat].Bet,
+		Raises:     s.Raises,
+	}
+	for _, st := range s.Seats {
+		r.Pot += st.Committed
+	}
+	return r
+}
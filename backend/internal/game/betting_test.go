@@ -0,0 +1,91 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// limitTable seats three 100-chip players with 1/2 blinds under l.
+func limitTable(t *testing.T, l Limit) State {
+	t.Helper()
+	s := newTable(t, 100, 100, 100)
+	s.Config.Limit = l
+	return start(t, s, stackedDeck(t))
+}
+
+func raiseRange(s State) (LegalAction, bool) {
+	for _, a := range s.LegalActions() {
+		if a.Type == Bet || a.Type == Raise {
+			return a, true
+		}
+	}
+	return LegalAction{}, false
+}
+
+func TestPotLimit(t *testing.T) {
+	s := limitTable(t, PotLimit)
+	// 3 in the pot and 2 to call: at most 2+3+2.
+	if r, _ := raiseRange(s); r.Min != 4 || r.Max != 7 {
+		t.Errorf("opening raise %d-%d, want 4-7", r.Min, r.Max)
+	}
+	if hasAction(s, AllIn) {
+		t.Error("all in for more than the pot is legal")
+	}
+	s = act(t, s, 0, Raise, 7)
+	if r, _ := raiseRange(s); r.Min != 12 || r.Max != 23 {
+		t.Errorf("small blind re-raise %d-%d, want 12-23", r.Min, r.Max)
+	}
+	_, err := s.Apply(Action{Seat: 1, Type: Raise, Amount: 24})
+	if e, ok := err.(*BetSizeError); !ok || e.Max != 23 || !IsInvalidAction(err) {
+		t.Errorf("raise to 24: err = %v, want a bet size error", err)
+	}
+}
+
+func TestFixedLimit(t *testing.T) {
+	s := limitTable(t, FixedLimit)
+	if _, err := s.Apply(Action{Seat: 0, Type: Raise, Amount: 5}); !IsBetSize(err) {
+		t.Errorf("raise to 5: err = %v, want a bet size error", err)
+	}
+	s = act(t, s, 0, Raise, 4)
+	s = act(t, s, 1, Raise, 6)
+	s = act(t, s, 2, Raise, 8) // the cap: blind, raise and two re-raises
+	if _, ok := raiseRange(s); ok || hasAction(s, AllIn) || !hasAction(s, Call) {
+		t.Errorf("capped betting: %v, want call or fold", s.LegalActions())
+	}
+	s = act(t, s, 0, Call, 0)
+	s = act(t, s, 1, Call, 0)
+	for s.Street != Turn {
+		s = act(t, s, s.ToAct, Check, 0)
+	}
+	if r, _ := raiseRange(s); r.Type != Bet || r.Min != 4 || r.Max != 4 {
+		t.Errorf("turn bet %+v, want the big bet of 4", r)
+	}
+}
+
+// flatRaise allows raises of exactly 10, to test a custom structure.
+type flatRaise struct{}
+
+func (flatRaise) Limits(r Round) (int64, int64, bool) {
+	return r.CurrentBet + 10, r.CurrentBet + 10, true
+}
+
+func TestCustomStructure(t *testing.T) {
+	s := newTable(t, 100, 100)
+	s.Config.Structure = flatRaise{}
+	s = start(t, s, stackedDeck(t))
+	if r, _ := raiseRange(s); r.Min != 12 || r.Max != 12 {
+		t.Errorf("raise %+v, want exactly 12", r)
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	for name, want := range map[string]Limit{"": NoLimit, "PL": PotLimit, "fixed_limit": FixedLimit} {
+		if l, err := ParseLimit(name); err != nil || l != want {
+			t.Errorf("ParseLimit(%q) = %v, %v, want %v", name, l, err, want)
+		}
+	}
+	if _, err := ParseLimit("spread"); !poker.IsInvalidInput(err) {
+		t.Errorf("ParseLimit(spread): err = %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package game
+
+import "fmt"
+
+// InvalidActionError reports a move the rules do not allow in the current
+// state, such as acting out of turn or raising too little.
+type InvalidActionError struct {
+	Msg string
+}
+
+func (e *InvalidActionError) Error() string {
+	return e.Msg
+}
+
+// BetSizeError reports a bet or raise outside the sizes the table's
+// betting structure allows.
+type BetSizeError struct {
+	Type     ActionType
+	Amount   int64
+	Min, Max int64
+}
+
+func (e *BetSizeError) Error() string {
+	if e.Min == e.Max {
+		return fmt.Sprintf("%v to %d: must be %d", e.Type, e.Amount, e.Min)
+	}
+	return fmt.Sprintf("%v to %d: must be %d-%d", e.Type, e.Amount, e.Min, e.Max)
+}
+
+// IsInvalidAction reports whether err rejects a move, bad bet sizes included.
+func IsInvalidAction(err error) bool {
+	switch err.(type) {
+	case *InvalidActionError, *BetSizeError:
+		return true
+	}
+	return false
+}
+
+func IsBetSize(err error) bool {
+	_, ok := err.(*BetSizeError)
+	return ok
+}
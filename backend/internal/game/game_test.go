@@ -0,0 +1,226 @@
+package game
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// newTable seats one player per stack, in seat order, with 1/2 blinds.
+func newTable(t *testing.T, stacks ...int64) State {
+	t.Helper()
+	s, err := NewTable(Config{SmallBlind: 1, BigBlind: 2, MaxSeats: len(stacks)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, stack := range stacks {
+		if s, err = s.Sit(i, string(rune('a'+i)), stack); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+// stackedDeck deals the given cards first, then the rest of the deck.
+func stackedDeck(t *testing.T, cards ...string) []poker.Card {
+	t.Helper()
+	top, err := poker.ParseCards(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rest := poker.CardSetOf(top)
+	for i := 0; i < poker.NumCards; i++ {
+		if c := poker.CardAt(i); !rest.Contains(c) {
+			top = append(top, c)
+		}
+	}
+	return top
+}
+
+func start(t *testing.T, s State, deck []poker.Card) State {
+	t.Helper()
+	s, err := s.StartHand(deck)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func act(t *testing.T, s State, seat int, typ ActionType, amount int64) State {
+	t.Helper()
+	next, err := s.Apply(Action{Seat: seat, Type: typ, Amount: amount})
+	if err != nil {
+		t.Fatalf("seat %d %v %d: %v", seat, typ, amount, err)
+	}
+	return next
+}
+
+func hasAction(s State, typ ActionType) bool {
+	for _, a := range s.LegalActions() {
+		if a.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func TestHeadsUp_BlindsAndOrder(t *testing.T) {
+	s := start(t, newTable(t, 100, 100), stackedDeck(t))
+	if s.Button != 0 || s.Seats[0].Bet != 1 || s.Seats[1].Bet != 2 || s.ToAct != 0 {
+		t.Fatalf("button %d bets %d/%d to act %d, want the button on the small blind to act", s.Button, s.Seats[0].Bet, s.Seats[1].Bet, s.ToAct)
+	}
+	want := []LegalAction{{Type: Fold}, {Type: Call, Min: 2, Max: 2}, {Type: Raise, Min: 4, Max: 100}, {Type: AllIn, Min: 100, Max: 100}}
+	if got := s.LegalActions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("legal actions = %v, want %v", got, want)
+	}
+	for _, st := range s.Seats {
+		if len(st.Hole) != 2 {
+			t.Errorf("%s holds %v", st.Player, st.Hole)
+		}
+	}
+
+	s = act(t, s, 0, Call, 0)
+	if s.ToAct != 1 || !hasAction(s, Check) {
+		t.Fatalf("big blind should have the option, to act %d %v", s.ToAct, s.LegalActions())
+	}
+	s = act(t, s, 1, Check, 0)
+	if s.Street != Flop || len(s.Board) != 3 || s.ToAct != 1 {
+		t.Errorf("after preflop: %v board %v to act %d, want the flop with the big blind first", s.Street, s.Board, s.ToAct)
+	}
+
+	// The next hand moves the button.
+	s = act(t, act(t, s, 1, Bet, 2), 0, Fold, 0)
+	if s.Street != Complete || s.Seats[1].Stack != 102 {
+		t.Fatalf("fold to a bet: %v stacks %d/%d", s.Street, s.Seats[0].Stack, s.Seats[1].Stack)
+	}
+	if s = start(t, s, stackedDeck(t)); s.Button != 1 || s.HandNumber != 2 {
+		t.Errorf("second hand: button %d hand %d", s.Button, s.HandNumber)
+	}
+}
+
+func TestFoldWinsUncalledBetReturned(t *testing.T) {
+	s := start(t, newTable(t, 100, 100, 100), stackedDeck(t))
+	s = act(t, s, 0, Raise, 6)
+	s = act(t, s, 1, Fold, 0)
+	s = act(t, s, 2, Fold, 0)
+	if s.Street != Complete || s.ToAct != -1 {
+		t.Fatalf("street %v to act %d, want complete", s.Street, s.ToAct)
+	}
+	if got := []int64{s.Seats[0].Stack, s.Seats[1].Stack, s.Seats[2].Stack}; !reflect.DeepEqual(got, []int64{103, 99, 98}) {
+		t.Errorf("stacks = %v, want [103 99 98]", got)
+	}
+	if s.Result.Won[0] != 5 || s.Result.Hands[0] != nil {
+		t.Errorf("result = %+v, want 5 won without a showdown", s.Result)
+	}
+}
+
+func TestShowdown_StateIsImmutable(t *testing.T) {
+	// Seat 1 is dealt first heads-up: KK against AA on a dry board.
+	deck := stackedDeck(t, "HK", "HA", "DK", "DA", "C4", "D7", "C9", "S2", "C5", "SJ", "C6", "D3")
+	s := start(t, newTable(t, 100, 100), deck)
+	before := s
+	s = act(t, s, 0, Call, 0)
+	if before.Seats[0].Bet != 1 || before.ToAct != 0 {
+		t.Fatalf("Apply changed its receiver: %+v", before.Seats[0])
+	}
+	s = act(t, s, 1, Check, 0)
+	for s.Street != Complete {
+		s = act(t, s, s.ToAct, Check, 0)
+	}
+	if s.Seats[0].Stack != 102 || s.Seats[1].Stack != 98 {
+		t.Errorf("stacks %d/%d, want aces to win 2", s.Seats[0].Stack, s.Seats[1].Stack)
+	}
+	if h := s.Result.Hands[0]; h == nil || h.Rank != poker.OnePair {
+		t.Errorf("seat 0 hand = %+v", h)
+	}
+	if !reflect.DeepEqual(s.Result.Pots, []poker.Pot{{Amount: 4, Eligible: []int{0, 1}, Winners: []int{0}, Payouts: []int64{4, 0}}}) {
+		t.Errorf("pots = %+v", s.Result.Pots)
+	}
+}
+
+func TestMinimumRaise(t *testing.T) {
+	s := start(t, newTable(t, 100, 100, 100), stackedDeck(t))
+	if _, err := s.Apply(Action{Seat: 0, Type: Raise, Amount: 3}); !IsInvalidAction(err) {
+		t.Errorf("raise to 3: err = %v, want invalid action", err)
+	}
+	if _, err := s.Apply(Action{Seat: 1, Type: Call}); !IsInvalidAction(err) {
+		t.Errorf("out of turn: err = %v, want invalid action", err)
+	}
+	s = act(t, s, 0, Raise, 10)
+	if s.MinRaise != 8 {
+		t.Errorf("min raise = %d, want 8", s.MinRaise)
+	}
+	if _, err := s.Apply(Action{Seat: 1, Type: Raise, Amount: 17}); !IsInvalidAction(err) {
+		t.Errorf("raise to 17: err = %v, want invalid action", err)
+	}
+	act(t, s, 1, Raise, 18)
+}
+
+func TestIncompleteAllInDoesNotReopenBetting(t *testing.T) {
+	s := start(t, newTable(t, 100, 100, 15), stackedDeck(t))
+	s = act(t, s, 0, Raise, 10)
+	s = act(t, s, 1, Call, 0)
+	s = act(t, s, 2, AllIn, 0) // 15 is a raise of 5, short of 8
+	if s.ToAct != 0 || s.CurrentBet != 15 {
+		t.Fatalf("to act %d current bet %d", s.ToAct, s.CurrentBet)
+	}
+	if hasAction(s, Raise) || hasAction(s, AllIn) || !hasAction(s, Call) {
+		t.Errorf("facing a short all-in after acting: %v, want call or fold only", s.LegalActions())
+	}
+	s = act(t, s, 0, Call, 0)
+	s = act(t, s, 1, Call, 0)
+	if s.Street != Flop || s.ToAct != 1 {
+		t.Errorf("street %v to act %d, want the flop with seat 1 first", s.Street, s.ToAct)
+	}
+}
+
+func TestSidePots(t *testing.T) {
+	// Deal order from seat 1: QQ to seat 2 would lose to KK in seat 1 and AA in seat 0.
+	deck := stackedDeck(t, "HK", "HQ", "HA", "DK", "DQ", "DA", "C4", "D7", "C9", "S2", "C5", "SJ", "C6", "D3")
+	s := start(t, newTable(t, 50, 100, 200), deck)
+	s = act(t, s, 0, AllIn, 0)
+	s = act(t, s, 1, AllIn, 0)
+	if hasAction(s, AllIn) || hasAction(s, Raise) {
+		t.Errorf("raising with nobody left to respond: %v", s.LegalActions())
+	}
+	s = act(t, s, 2, Call, 0)
+	if s.Street != Complete || len(s.Board) != 5 {
+		t.Fatalf("street %v board %v, want the board run out", s.Street, s.Board)
+	}
+	want := []poker.Pot{
+		{Amount: 150, Eligible: []int{0, 1, 2}, Winners: []int{0}, Payouts: []int64{150, 0, 0}},
+		{Amount: 100, Eligible: []int{1, 2}, Winners: []int{1}, Payouts: []int64{0, 100, 0}},
+	}
+	if !reflect.DeepEqual(s.Result.Pots, want) {
+		t.Errorf("pots = %+v, want %+v", s.Result.Pots, want)
+	}
+	if got := []int64{s.Seats[0].Stack, s.Seats[1].Stack, s.Seats[2].Stack}; !reflect.DeepEqual(got, []int64{150, 100, 100}) {
+		t.Errorf("stacks = %v, want [150 100 100]", got)
+	}
+}
+
+func TestSitAndStand(t *testing.T) {
+	s := newTable(t, 100, 100)
+	if _, err := s.Sit(0, "x", 100); !IsInvalidAction(err) {
+		t.Errorf("sit in a taken seat: err = %v", err)
+	}
+	s = start(t, s, stackedDeck(t))
+	if _, _, err := s.Stand(0); !IsInvalidAction(err) {
+		t.Errorf("stand mid-hand: err = %v", err)
+	}
+	if _, err := (State{}).StartHand(nil); err == nil {
+		t.Error("starting a hand at an empty table succeeded")
+	}
+}
+
+func TestDeckExhausted(t *testing.T) {
+	s := start(t, newTable(t, 100, 100), stackedDeck(t))
+	s = act(t, s, 0, Call, 0)
+	s.Deck = poker.Deck{}
+	var deckErr *poker.DeckError
+	if _, err := s.Apply(Action{Seat: 1, Type: Check}); !errors.As(err, &deckErr) {
+		t.Errorf("dealing the flop from an empty deck: err = %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// StartHand moves the button, posts the blinds and deals from deck, which
+// holds the shuffled cards in dealing order. Every seated player with chips
+// is dealt in.
+func (s State) StartHand(deck []poker.Card) (State, error) {
+	if s.InHand() {
+		return State{}, &InvalidActionError{Msg: "a hand is in progress"}
+	}
+	funded := func(st Seat) bool { return st.Player != "" && st.Stack > 0 }
+	players := s.count(funded)
+	if players < 2 {
+		return State{}, &InvalidActionError{Msg: "need 2 players with chips"}
+	}
+	holeCards := s.Config.Variant.HoleCards()
+	if need := players*holeCards + 8; len(deck) < need {
+		return State{}, &poker.InvalidInputError{Msg: fmt.Sprintf("deck has %d cards, need %d", len(deck), need)}
+	}
+	var seen poker.CardSet
+	for _, c := range deck {
+		if seen.Contains(c) {
+			return State{}, &poker.DuplicateCardError{Card: c.String()}
+		}
+		if !s.Config.Variant.Deck().Contains(c) {
+			return State{}, &poker.InvalidInputError{Msg: fmt.Sprintf("%s is not in the %v deck", c, s.Config.Variant)}
+		}
+		seen.Add(c)
+	}
+
+	s = s.clone()
+	s.HandNumber++
+	s.Result = nil
+	s.Board = nil
+	s.Deck = *poker.DeckOf(deck)
+	for i, st := range s.Seats {
+		s.Seats[i] = Seat{Player: st.Player, Stack: st.Stack, InHand: funded(st)}
+	}
+	inHand := func(st Seat) bool { return st.InHand }
+	s.Button = s.next(s.Button, inHand)
+
+	// Heads-up the button posts the small blind.
+	sb := s.next(s.Button, inHand)
+	if players == 2 {
+		sb = s.Button
+	}
+	bb := s.next(sb, inHand)
+	s.put(sb, s.Config.SmallBlind)
+	s.put(bb, s.Config.BigBlind)
+	s.CurrentBet = s.Config.BigBlind
+	s.MinRaise = s.Config.BigBlind
+	s.Raises = 1
+
+	for round := 0; round < holeCards; round++ {
+		for seat := s.next(s.Button, inHand); ; seat = s.next(seat, inHand) {
+			card, err := s.Deck.Deal(1)
+			if err != nil {
+				return State{}, err
+			}
+			s.Seats[seat].Hole = append(s.Seats[seat].Hole, card...)
+			if seat == s.Button {
+				break
+			}
+		}
+	}
+
+	s.Street = Preflop
+	s.startHistory(sb, bb)
+	s.ToAct = s.next(bb, needsAction(s.CurrentBet))
+	if s.ToAct < 0 {
+		if err := s.endRound(); err != nil {
+			return State{}, err
+		}
+	}
+	return s, nil
+}
+
+// needsAction reports whether a seat must still act facing currentBet.
+func needsAction(currentBet int64) func(Seat) bool {
+	return func(st Seat) bool {
+		return st.canAct() && (!st.Acted || st.Bet < currentBet)
+	}
+}
+
+// put moves up to amount chips from a seat's stack into its bet.
+func (s *State) put(seat int, amount int64) {
+	st := &s.Seats[seat]
+	if amount >= st.Stack {
+		amount = st.Stack
+		st.AllIn = true
+	}
+	st.Stack -= amount
+	st.Bet += amount
+	st.Committed += amount
+}
+
+// afterAction passes the turn on from seat, ending the betting round or the
+// hand when nobody is left to act.
+func (s *State) afterAction(seat int) error {
+	if s.count(func(st Seat) bool { return st.InHand }) == 1 {
+		s.award()
+		return nil
+	}
+	s.ToAct = s.next(seat, needsAction(s.CurrentBet))
+	if s.ToAct < 0 {
+		return s.endRound()
+	}
+	return nil
+}
+
+// endRound closes the street's betting and deals the next street, running
+// the board out when at most one player can still bet. It fails when the
+// deck runs out.
+func (s *State) endRound() error {
+	for {
+		for i := range s.Seats {
+			s.Seats[i].Bet = 0
+			s.Seats[i].Acted = false
+		}
+		s.CurrentBet = 0
+		s.MinRaise = s.Config.BigBlind
+		s.Raises = 0
+		if s.Street == River {
+			s.award()
+			return nil
+		}
+
+		if err := s.Deck.Burn(); err != nil {
+			return err
+		}
+		n := 1
+		if s.Street == Preflop {
+			n = 3
+		}
+		cards, err := s.Deck.Deal(n)
+		if err != nil {
+			return err
+		}
+		s.Board = append(s.Board, cards...)
+		s.Street++
+
+		if s.count(Seat.canAct) > 1 {
+			s.ToAct = s.next(s.Button, Seat.canAct)
+			return nil
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package game
+
+import (
+	"time"
+
+	"github.com/texas-holdem/backend/internal/deck"
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// HandHistory is the record of one dealt hand, kept in State.History as the
+// hand is played.
+type HandHistory struct {
+	ID         string          `json:"id,omitempty"`
+	Table      string          `json:"table,omitempty"`
+	HandNumber int             `json:"hand_number"`
+	Number     int64           `json:"number,omitempty"` // the store's count of hands across all tables; 0 until stored
+	Time       time.Time       `json:"time"`             // when the hand was stored; zero until then
+	Config     Config          `json:"config"`
+	Button     int             `json:"button"`
+	Seats      []HistorySeat   `json:"seats"` // the players dealt in, in seat order
+	Blinds     []Blind         `json:"blinds"`
+	Actions    []HistoryAction `json:"actions"`
+	Board      []poker.Card    `json:"board"`
+	Result     *Result         `json:"result,omitempty"`  // set once the hand is over
+	Shuffle    *deck.Proof     `json:"shuffle,omitempty"` // how the deck was shuffled, when it was shuffled fairly
+}
+
+// HistorySeat is a player dealt into the hand.
+type HistorySeat struct {
+	Seat   int          `json:"seat"`
+	Player string       `json:"player"`
+	Stack  int64        `json:"stack"`                // before the blinds
+	Hole   []poker.Card `json:"hole_cards,omitempty"` // in dealing order; nil when hidden
+	Token  string       `json:"token,omitempty"`      // the player's seat token, kept to prove them; For drops it
+}
+
+// Blind is a forced bet posted as the hand started.
+type Blind struct {
+	Seat   int   `json:"seat"`
+	Amount int64 `json:"amount"`
+}
+
+// HistoryAction is one move and what it did.
+type HistoryAction struct {
+	Street Street     `json:"street"`
+	Seat   int        `json:"seat"`
+	Type   ActionType `json:"type"`
+	Amount int64      `json:"amount,omitempty"` // as requested: the total after a bet or raise
+	Put    int64      `json:"put"`              // chips the move added
+	Bet    int64      `json:"bet"`              // the seat's total bet on the street afterwards
+	Raise  int64      `json:"raise,omitempty"`  // how far the move raised the current bet
+	AllIn  bool       `json:"all_in,omitempty"` // the move put the seat all in
+}
+
+// Shown reports whether seat's cards were shown down.
+func (h HandHistory) Shown(seat int) bool {
+	return h.Result != nil && seat < len(h.Result.Hands) && h.Result.Hands[seat] != nil
+}
+
+// DealtIn reports whether player was dealt into the hand.
+func (h HandHistory) DealtIn(player string) bool {
+	for _, st := range h.Seats {
+		if player != "" && st.Player == player {
+			return true
+		}
+	}
+	return false
+}
+
+// For returns the history as player may see it: other players' hole cards
+// are removed unless they were shown down. An empty player sees only the
+// shown cards. Seat tokens are always removed, and Shuffle, which rebuilds
+// every card dealt, is kept only for a player dealt in.
+func (h HandHistory) For(player string) HandHistory {
+	if !h.DealtIn(player) {
+		h.Shuffle = nil
+	}
+	h.Seats = append([]HistorySeat(nil), h.Seats...)
+	for i, st := range h.Seats {
+		if (player == "" || st.Player != player) && !h.Shown(st.Seat) {
+			h.Seats[i].Hole = nil
+		}
+		h.Seats[i].Token = ""
+	}
+	return h
+}
+
+// record updates the hand's history, leaving the copy earlier states hold
+// untouched.
+func (s *State) record(fn func(h *HandHistory)) {
+	if s.History == nil {
+		return
+	}
+	h := *s.History
+	h.Actions = h.Actions[:len(h.Actions):len(h.Actions)] // appending copies
+	fn(&h)
+	s.History = &h
+}
+
+// startHistory opens the history of the hand just dealt; stacks are taken
+// before the blinds.
+func (s *State) startHistory(sb, bb int) {
+	h := &HandHistory{
+		HandNumber: s.HandNumber,
+		Config:     s.Config,
+		Button:     s.Button,
+	}
+	for i, st := range s.Seats {
+		if st.InHand {
+			h.Seats = append(h.Seats, HistorySeat{Seat: i, Player: st.Player, Stack: st.Stack + st.Committed, Hole: st.Hole})
+		}
+	}
+	h.Blinds = []Blind{{Seat: sb, Amount: s.Seats[sb].Committed}, {Seat: bb, Amount: s.Seats[bb].Committed}}
+	s.History = h
+}
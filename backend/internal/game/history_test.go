@@ -0,0 +1,110 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/texas-holdem/backend/internal/deck"
+)
+
+func TestHandHistory_Showdown(t *testing.T) {
+	// Seat 1 is dealt first heads-up: KK against AA on a dry board.
+	deck := stackedDeck(t, "HK", "HA", "DK", "DA", "C4", "D7", "C9", "S2", "C5", "SJ", "C6", "D3")
+	s := start(t, newTable(t, 100, 100), deck)
+	first := s.History
+	s = act(t, s, 0, Raise, 6)
+	s = act(t, s, 1, Call, 0)
+	s = act(t, s, 1, Bet, 4)
+	s = act(t, s, 0, Call, 0)
+	for s.Street != Complete {
+		s = act(t, s, s.ToAct, Check, 0)
+	}
+	if len(first.Actions) != 0 || first.Result != nil {
+		t.Errorf("earlier state's history changed: %+v", first)
+	}
+
+	h := *s.History
+	h.ID, h.Number, h.Table, h.Time = "t1-1", 3, "t1", time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	if len(h.Actions) != 8 || h.Actions[0].Raise != 4 || h.Actions[2].Street != Flop || h.Result == nil || len(h.Board) != 5 {
+		t.Fatalf("history = %+v", h)
+	}
+
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back HandHistory
+	if err := json.Unmarshal(b, &back); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(back.Seats, h.Seats) || !reflect.DeepEqual(back.Actions, h.Actions) || !strings.Contains(string(b), `"hole_cards":["HA","DA"]`) {
+		t.Errorf("JSON round trip: %s", b)
+	}
+
+	text := h.PokerStars("a")
+	for _, want := range []string{
+		"PokerStars Hand #3: Hold'em No Limit (1/2) - 2026/10/18 12:00:00 UTC\n",
+		"Table 't1' 2-max Seat #1 is the button\n",
+		"Seat 1: a (100 in chips)\n",
+		"a: posts small blind 1\nb: posts big blind 2\n",
+		"*** HOLE CARDS ***\nDealt to a [Ah Ad]\na: raises",
+		"a: raises 4 to 6\nb: calls 4\n*** FLOP *** [7d 9c 2s]\nb: bets 4\na: calls 4\n",
+		"*** TURN *** [7d 9c 2s] [Js]\n",
+		"*** RIVER *** [7d 9c 2s Js] [3d]\n",
+		"*** SHOW DOWN ***\na: shows [Ah Ad] (One Pair)\n",
+		"a collected 20 from pot\n",
+		"Total pot 20 | Rake 0\nBoard [7d 9c 2s Js 3d]\n",
+		"Seat 1: a (button) (small blind) showed [Ah Ad] and won (20) with One Pair\n",
+		"Seat 2: b (big blind) showed [Kh Kd] and lost with One Pair\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in\n%s", want, text)
+		}
+	}
+	if strings.Contains(h.PokerStars(""), "Dealt to") {
+		t.Error("an export without a hero lists dealt cards")
+	}
+}
+
+func TestHandHistory_FoldHidesCards(t *testing.T) {
+	s := start(t, newTable(t, 100, 100, 100), stackedDeck(t))
+	s = act(t, s, 0, Raise, 6)
+	s = act(t, s, 1, Fold, 0)
+	s = act(t, s, 2, AllIn, 0)
+	s = act(t, s, 0, Fold, 0)
+
+	s.History.Shuffle = &deck.Proof{ServerSeed: "secret"}
+	if s.History.For("").Shuffle != nil || s.History.For("x").Shuffle != nil {
+		t.Error("the shuffle was kept for a player not dealt in")
+	}
+	h := s.History.For("b")
+	if h.Shuffle == nil {
+		t.Error("b lost the shuffle")
+	}
+	for _, st := range h.Seats {
+		if (st.Player == "b") != (st.Hole != nil) {
+			t.Errorf("seat %d hole cards %v as seen by b", st.Seat, st.Hole)
+		}
+	}
+	if s.History.Seats[0].Hole == nil {
+		t.Error("For changed the original history")
+	}
+	text := h.PokerStars("b")
+	for _, want := range []string{
+		"*** HOLE CARDS ***\nDealt to b [",
+		"c: raises 94 to 100 and is all-in\n",
+		"Uncalled bet (94) returned to c\nc collected 13 from pot\n",
+		"Seat 1: a (button) folded before Flop\n",
+		"Seat 3: c (big blind) collected (13)\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("missing %q in\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "Dealt to a") || strings.Contains(text, "SHOW DOWN") {
+		t.Errorf("hidden cards or a showdown in\n%s", text)
+	}
+}
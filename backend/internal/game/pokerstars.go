@@ -0,0 +1,227 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+var pokerStarsGames = map[poker.Variant]string{
+	poker.Holdem:    "Hold'em",
+	poker.Omaha4:    "Omaha",
+	poker.Omaha5:    "5 Card Omaha",
+	poker.Omaha8:    "Omaha Hi/Lo",
+	poker.ShortDeck: "6+ Hold'em",
+}
+
+var pokerStarsLimits = map[Limit]string{
+	NoLimit:    "No Limit",
+	PotLimit:   "Pot Limit",
+	FixedLimit: "Limit",
+}
+
+var pokerStarsStreets = map[Street]string{
+	Flop:  "Flop",
+	Turn:  "Turn",
+	River: "River",
+}
+
+// PokerStars writes the history in the PokerStars hand history text format
+// that tracking tools import, as seen by hero. Chips are written as plain
+// numbers and seats are numbered from 1. Only hero's cards are listed as
+// "Dealt to", as tracking tools take that player for the hero; an empty hero
+// gets no such line. Other cards appear only where they were shown down.
+func (h HandHistory) PokerStars(hero string) string {
+	var b strings.Builder
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&b, format+"\n", args...)
+	}
+	name := func(seat int) string {
+		for _, st := range h.Seats {
+			if st.Seat == seat {
+				return st.Player
+			}
+		}
+		return fmt.Sprintf("seat %d", seat+1)
+	}
+
+	line("PokerStars Hand #%d: %s %s (%d/%d) - %s", h.pokerStarsNumber(),
+		pokerStarsGames[h.Config.Variant], pokerStarsLimits[h.Config.Limit],
+		h.Config.SmallBlind, h.Config.BigBlind, h.Time.UTC().Format("2006/01/02 15:04:05 UTC"))
+	line("Table '%s' %d-max Seat #%d is the button", h.Table, h.Config.MaxSeats, h.Button+1)
+	for _, st := range h.Seats {
+		line("Seat %d: %s (%d in chips)", st.Seat+1, st.Player, st.Stack)
+	}
+	for i, bl := range h.Blinds {
+		kind := "small"
+		if i == 1 {
+			kind = "big"
+		}
+		line("%s: posts %s blind %d", name(bl.Seat), kind, bl.Amount)
+	}
+	line("*** HOLE CARDS ***")
+	for _, st := range h.Seats {
+		if hero != "" && st.Player == hero && len(st.Hole) > 0 {
+			line("Dealt to %s [%s]", st.Player, pokerStarsCards(st.Hole))
+		}
+	}
+
+	for street := Preflop; street <= River; street++ {
+		if street > Preflop {
+			n := 3 + int(street-Flop)
+			if len(h.Board) < n {
+				break
+			}
+			title := strings.ToUpper(pokerStarsStreets[street])
+			if street == Flop {
+				line("*** %s *** [%s]", title, pokerStarsCards(h.Board[:n]))
+			} else {
+				line("*** %s *** [%s] [%s]", title, pokerStarsCards(h.Board[:n-1]), pokerStarsCards(h.Board[n-1:n]))
+			}
+		}
+		for _, a := range h.Actions {
+			if a.Street == street {
+				line("%s: %s", name(a.Seat), pokerStarsAction(a))
+			}
+		}
+	}
+
+	res := h.Result
+	if res == nil {
+		return b.String()
+	}
+	for seat, chips := range res.Returned {
+		if chips > 0 {
+			line("Uncalled bet (%d) returned to %s", chips, name(seat))
+		}
+	}
+	showdown := false
+	for _, st := range h.Seats {
+		if hand := res.Hands[st.Seat]; hand != nil {
+			if !showdown {
+				line("*** SHOW DOWN ***")
+				showdown = true
+			}
+			line("%s: shows [%s] (%s)", st.Player, pokerStarsCards(st.Hole), pokerStarsHand(hand, h.Config.Variant))
+		}
+	}
+	var total int64
+	for i, pot := range res.Pots {
+		total += pot.Amount
+		for seat, chips := range pot.Payouts {
+			if chips > 0 {
+				line("%s collected %d from %s", name(seat), chips, pokerStarsPot(i, len(res.Pots)))
+			}
+		}
+	}
+
+	line("*** SUMMARY ***")
+	summary := fmt.Sprintf("Total pot %d", total)
+	for i, pot := range res.Pots {
+		if i == 0 && len(res.Pots) > 1 {
+			summary += fmt.Sprintf(" Main pot %d.", pot.Amount)
+		} else if i > 0 {
+			summary += fmt.Sprintf(" Side pot-%d %d.", i, pot.Amount)
+		}
+	}
+	line("%s | Rake 0", summary)
+	if len(h.Board) > 0 {
+		line("Board [%s]", pokerStarsCards(h.Board))
+	}
+	for _, st := range h.Seats {
+		roles := ""
+		if st.Seat == h.Button {
+			roles += " (button)"
+		}
+		if st.Seat == h.Blinds[0].Seat {
+			roles += " (small blind)"
+		} else if st.Seat == h.Blinds[1].Seat {
+			roles += " (big blind)"
+		}
+		line("Seat %d: %s%s %s", st.Seat+1, st.Player, roles, h.pokerStarsOutcome(st))
+	}
+	return b.String()
+}
+
+// pokerStarsOutcome sums up how the hand went for a seat.
+func (h HandHistory) pokerStarsOutcome(st HistorySeat) string {
+	for _, a := range h.Actions {
+		if a.Seat == st.Seat && a.Type == Fold {
+			if a.Street == Preflop {
+				return "folded before Flop"
+			}
+			return "folded on the " + pokerStarsStreets[a.Street]
+		}
+	}
+	won := h.Result.Won[st.Seat]
+	if hand := h.Result.Hands[st.Seat]; hand != nil {
+		if won > 0 {
+			return fmt.Sprintf("showed [%s] and won (%d) with %s", pokerStarsCards(st.Hole), won, pokerStarsHand(hand, h.Config.Variant))
+		}
+		return fmt.Sprintf("showed [%s] and lost with %s", pokerStarsCards(st.Hole), pokerStarsHand(hand, h.Config.Variant))
+	}
+	return fmt.Sprintf("collected (%d)", won)
+}
+
+func pokerStarsAction(a HistoryAction) string {
+	var s string
+	switch {
+	case a.Type == Fold:
+		return "folds"
+	case a.Type == Check:
+		return "checks"
+	case a.Raise > 0 && a.Raise == a.Bet:
+		s = fmt.Sprintf("bets %d", a.Bet)
+	case a.Raise > 0:
+		s = fmt.Sprintf("raises %d to %d", a.Raise, a.Bet)
+	default:
+		s = fmt.Sprintf("calls %d", a.Put)
+	}
+	if a.AllIn {
+		s += " and is all-in"
+	}
+	return s
+}
+
+// pokerStarsHand describes a shown hand, with its low in hi-lo games.
+func pokerStarsHand(h *poker.EvaluatedHand, v poker.Variant) string {
+	if !v.HiLo() {
+		return h.RankName
+	}
+	if h.Low == nil {
+		return "HI: " + h.RankName
+	}
+	return fmt.Sprintf("HI: %s; LO: %s", h.RankName, strings.ReplaceAll(h.Low.Name, "-", ","))
+}
+
+// pokerStarsPot names pot i of n.
+func pokerStarsPot(i, n int) string {
+	switch {
+	case n == 1:
+		return "pot"
+	case i == 0:
+		return "main pot"
+	}
+	return fmt.Sprintf("side pot-%d", i)
+}
+
+// pokerStarsCards writes cards as e.g. "Ah Td".
+func pokerStarsCards(cards []poker.Card) string {
+	parts := make([]string, len(cards))
+	for i, c := range cards {
+		s := c.String()
+		parts[i] = s[1:] + strings.ToLower(s[:1])
+	}
+	return strings.Join(parts, " ")
+}
+
+// pokerStarsNumber is the hand number of the PokerStars header. Tracking
+// tools tell hands apart by it, and HandNumber repeats across tables, so it
+// is the store's Number. A hand not yet stored falls back to its HandNumber.
+func (h HandHistory) pokerStarsNumber() int64 {
+	if h.Number == 0 {
+		return int64(h.HandNumber)
+	}
+	return h.Number
+}
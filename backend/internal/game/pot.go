@@ -0,0 +1,87 @@
+package game
+
+import (
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// Result is how a hand ended.
+type Result struct {
+	Pots  []poker.Pot            `json:"pots"`  // main pot first
+	Hands []*poker.EvaluatedHand `json:"hands"` // per seat; nil unless shown down
+	Won   []int64                `json:"won"`   // chips each seat collected, uncalled chips aside
+
+	Returned []int64 `json:"returned"` // uncalled chips handed back, per seat
+}
+
+// award shows the remaining hands down, splits the chips into side pots
+// with poker.SplitPots and pays the winners.
+func (s *State) award() {
+	res := &Result{
+		Hands: make([]*poker.EvaluatedHand, len(s.Seats)),
+		Won:   make([]int64, len(s.Seats)),
+	}
+	cfg := poker.PotConfig{
+		Contributions: make([]int64, len(s.Seats)),
+		Folded:        make([]bool, len(s.Seats)),
+		Button:        s.Button,
+	}
+	for i, st := range s.Seats {
+		cfg.Contributions[i] = st.Committed
+		cfg.Folded[i] = !st.InHand
+	}
+	if s.count(func(st Seat) bool { return st.InHand }) > 1 {
+		s.showdown(res, &cfg)
+	}
+	split, err := poker.SplitPots(cfg)
+	if err != nil {
+		// Every live seat is ranked and no commitment is negative.
+		panic("game: split pots: " + err.Error())
+	}
+	res.Pots = split.Pots
+	res.Returned = split.Refunds
+	for i := range s.Seats {
+		res.Won[i] = split.Payouts[i] - split.Refunds[i]
+		s.Seats[i].Committed -= split.Refunds[i]
+		s.Seats[i].Stack += split.Payouts[i]
+		s.Seats[i].Bet = 0
+	}
+	s.Street = Complete
+	s.ToAct = -1
+	s.CurrentBet = 0
+	s.Result = res
+	s.record(func(h *HandHistory) {
+		h.Board = s.Board
+		h.Result = res
+	})
+}
+
+// showdown evaluates the hands still in and ranks the seats for cfg.
+func (s *State) showdown(res *Result, cfg *poker.PotConfig) {
+	var seats []int
+	var holes [][]poker.Card
+	for i, st := range s.Seats {
+		if st.InHand {
+			seats = append(seats, i)
+			holes = append(holes, st.Hole)
+		}
+	}
+	sd, err := s.Config.Variant.Showdown(s.Board, holes)
+	if err != nil {
+		// StartHand checked the deck, so every hand is valid.
+		panic("game: showdown: " + err.Error())
+	}
+	for i, seat := range seats {
+		h := sd.Hands[i]
+		res.Hands[seat] = &h
+	}
+	toSeats := func(tiers [][]int) [][]int {
+		for _, tier := range tiers {
+			for j, p := range tier {
+				tier[j] = seats[p]
+			}
+		}
+		return tiers
+	}
+	cfg.Ranking = toSeats(sd.Tiers())
+	cfg.LowRanking = toSeats(sd.LowTiers())
+}
@@ -0,0 +1,193 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// ReplayStep is the table during a recorded hand, after the deal or after
+// one of its actions.
+type ReplayStep struct {
+	Action     *HistoryAction `json:"action,omitempty"` // nil for the deal
+	Street     Street         `json:"street"`
+	Board      []poker.Card   `json:"board"`
+	Pot        int64          `json:"pot"`
+	CurrentBet int64          `json:"current_bet"`
+	ToAct      int            `json:"to_act"`
+	Seats      []ReplaySeat   `json:"seats"` // the players dealt in, in seat order
+}
+
+// ReplaySeat is one player's chips during a replay.
+type ReplaySeat struct {
+	Seat   int    `json:"seat"`
+	Player string `json:"player"`
+	Stack  int64  `json:"stack"`
+	Bet    int64  `json:"bet"`
+	InHand bool   `json:"in_hand"`
+	AllIn  bool   `json:"all_in"`
+}
+
+// Replay plays a recorded hand again and returns the table after the deal
+// and after every action. Hole cards the history hides are stood in for by
+// unseen cards; they can only matter at a showdown, where they are shown.
+func Replay(h HandHistory) ([]ReplayStep, error) {
+	s, err := NewTable(h.Config)
+	if err != nil {
+		return nil, err
+	}
+	for _, st := range h.Seats {
+		if s, err = s.Sit(st.Seat, st.Player, st.Stack); err != nil {
+			return nil, err
+		}
+	}
+	deck, err := replayDeck(h)
+	if err != nil {
+		return nil, err
+	}
+	// StartHand moves the button to the next seat dealt in.
+	s.Button = (h.Button - 1 + len(s.Seats)) % len(s.Seats)
+	s.HandNumber = h.HandNumber - 1
+	if s, err = s.StartHand(deck); err != nil {
+		return nil, err
+	}
+	if s.Button != h.Button {
+		return nil, &poker.InvalidInputError{Msg: fmt.Sprintf("seat %d cannot hold the button", h.Button)}
+	}
+
+	steps := []ReplayStep{replayStep(s, h, nil)}
+	for i := range h.Actions {
+		a := h.Actions[i]
+		if s, err = s.Apply(Action{Seat: a.Seat, Type: a.Type, Amount: a.Amount}); err != nil {
+			return nil, &poker.InvalidInputError{Msg: fmt.Sprintf("action %d: %v", i, err)}
+		}
+		steps = append(steps, replayStep(s, h, &h.Actions[i]))
+	}
+	return steps, nil
+}
+
+func replayStep(s State, h HandHistory, a *HistoryAction) ReplayStep {
+	step := ReplayStep{
+		Action:     a,
+		Street:     s.Street,
+		Board:      s.Board,
+		CurrentBet: s.CurrentBet,
+		ToAct:      s.ToAct,
+	}
+	for _, hs := range h.Seats {
+		st := s.Seats[hs.Seat]
+		step.Pot += st.Committed
+		step.Seats = append(step.Seats, ReplaySeat{
+			Seat:   hs.Seat,
+			Player: st.Player,
+			Stack:  st.Stack,
+			Bet:    st.Bet,
+			InHand: st.InHand,
+			AllIn:  st.AllIn,
+		})
+	}
+	return step
+}
+
+// replayDeck stacks a deck that deals h's hole cards and board in the order
+// StartHand and the streets draw them, filling in for hidden cards.
+func replayDeck(h HandHistory) ([]poker.Card, error) {
+	order, err := dealOrder(h)
+	if err != nil {
+		return nil, err
+	}
+	var known []poker.Card
+	for _, c := range order {
+		if c != nil {
+			known = append(known, *c)
+		}
+	}
+	used := poker.CardSetOf(known)
+	if used.Count() != len(known) {
+		return nil, &poker.InvalidInputError{Msg: "duplicate card in hand history"}
+	}
+	spare := (h.Config.Variant.Deck() &^ used).Cards()
+	deck := make([]poker.Card, 0, h.Config.Variant.Deck().Count())
+	for _, c := range order {
+		if c != nil {
+			deck = append(deck, *c)
+		} else {
+			deck = append(deck, spare[0])
+			spare = spare[1:]
+		}
+	}
+	return append(deck, spare...), nil
+}
+
+// DealtFrom reports whether deck, drawn from its start, deals h's hole cards
+// and board. Hidden cards and burns match any card.
+func (h HandHistory) DealtFrom(deck []poker.Card) (bool, error) {
+	order, err := dealOrder(h)
+	if err != nil {
+		return false, err
+	}
+	if len(deck) < len(order) {
+		return false, nil
+	}
+	for i, c := range order {
+		if c != nil && *c != deck[i] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// dealOrder lists the cards of h in the order StartHand and the streets draw
+// them from the deck, through the river; nil stands for a hidden card or a
+// burn.
+func dealOrder(h HandHistory) ([]*poker.Card, error) {
+	holeCards := h.Config.Variant.HoleCards()
+	if need := len(h.Seats)*holeCards + 8; need > h.Config.Variant.Deck().Count() {
+		return nil, &poker.InvalidInputError{Msg: fmt.Sprintf("%d players cannot be dealt from one deck", len(h.Seats))}
+	}
+	for i, st := range h.Seats {
+		if i > 0 && st.Seat <= h.Seats[i-1].Seat {
+			return nil, &poker.InvalidInputError{Msg: "seats must be in seat order"}
+		}
+		if st.Hole != nil && len(st.Hole) != holeCards {
+			return nil, &poker.InvalidInputError{Msg: fmt.Sprintf("seat %d: need %d hole cards", st.Seat, holeCards)}
+		}
+	}
+	if len(h.Board) > 5 {
+		return nil, &poker.InvalidInputError{Msg: "max 5 board cards"}
+	}
+
+	// Hole cards go round from the seat after the button, which is last.
+	seats := make([]HistorySeat, 0, len(h.Seats))
+	for i, st := range h.Seats {
+		if st.Seat > h.Button {
+			seats = append(append(seats, h.Seats[i:]...), h.Seats[:i]...)
+			break
+		}
+	}
+	if len(seats) == 0 {
+		seats = h.Seats
+	}
+	var order []*poker.Card
+	for r := 0; r < holeCards; r++ {
+		for _, st := range seats {
+			var c *poker.Card
+			if st.Hole != nil {
+				c = &st.Hole[r]
+			}
+			order = append(order, c)
+		}
+	}
+	// A burn before the flop, turn and river.
+	for i := 0; i < 5; i++ {
+		if i == 0 || i >= 3 {
+			order = append(order, nil)
+		}
+		var c *poker.Card
+		if i < len(h.Board) {
+			c = &h.Board[i]
+		}
+		order = append(order, c)
+	}
+	return order, nil
+}
@@ -0,0 +1,57 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+func TestReplay(t *testing.T) {
+	deck := stackedDeck(t, "HK", "HQ", "HA", "DK", "DQ", "DA", "C4", "D7", "C9", "S2", "C5", "SJ", "C6", "D3")
+	s := start(t, newTable(t, 50, 100, 200), deck)
+	s = act(t, s, 0, Raise, 10)
+	s = act(t, s, 1, Call, 0)
+	s = act(t, s, 2, Fold, 0)
+	s = act(t, s, 1, Check, 0)
+	s = act(t, s, 0, AllIn, 0)
+	s = act(t, s, 1, Call, 0)
+	h := *s.History
+
+	// Seat 1 sees only its own cards and those shown down.
+	steps, err := Replay(h.For("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != len(h.Actions)+1 || steps[0].Action != nil || steps[0].Pot != 3 || steps[0].ToAct != 0 {
+		t.Fatalf("steps = %+v", steps)
+	}
+	if st := steps[3]; st.Street != Flop || st.Pot != 22 || len(st.Board) != 3 || st.Seats[2].InHand {
+		t.Errorf("after the fold: %+v", st)
+	}
+	last := steps[len(steps)-1]
+	if last.Street != Complete || last.Pot != 102 || len(last.Board) != 5 {
+		t.Errorf("last step: %+v", last)
+	}
+	for i, st := range last.Seats {
+		if st.Stack != s.Seats[i].Stack {
+			t.Errorf("seat %d: replayed stack %d, played %d", i, st.Stack, s.Seats[i].Stack)
+		}
+	}
+
+	for _, hist := range []HandHistory{h, h.For("b")} {
+		if ok, err := hist.DealtFrom(deck); !ok || err != nil {
+			t.Errorf("history not dealt from its deck: %v", err)
+		}
+	}
+	swapped := append([]poker.Card(nil), deck...)
+	swapped[0], swapped[1] = swapped[1], swapped[0]
+	if ok, _ := h.DealtFrom(swapped); ok {
+		t.Error("history dealt from another deck")
+	}
+
+	bad := h
+	bad.Actions = append([]HistoryAction{{Seat: 2, Type: Check}}, h.Actions...)
+	if _, err := Replay(bad); !poker.IsInvalidInput(err) {
+		t.Errorf("action out of turn: err = %v, want invalid input", err)
+	}
+}
@@ -0,0 +1,204 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// Config fixes the rules of a table.
+type Config struct {
+	Variant    poker.Variant `json:"variant"`
+	Limit      Limit         `json:"limit"`
+	SmallBlind int64         `json:"small_blind"`
+	BigBlind   int64         `json:"big_blind"`
+	MaxSeats   int           `json:"max_seats"` // 2-10
+
+	// Structure, when set, sizes bets in place of Limit.
+	Structure Structure `json:"-"`
+}
+
+func (c Config) validate() error {
+	if c.MaxSeats < 2 || c.MaxSeats > 10 {
+		return &poker.InvalidInputError{Msg: "max_seats must be 2-10"}
+	}
+	if c.SmallBlind <= 0 || c.BigBlind < c.SmallBlind {
+		return &poker.InvalidInputError{Msg: "need 0 < small blind <= big blind"}
+	}
+	if !c.Variant.Valid() {
+		return &poker.InvalidInputError{Msg: "unknown variant"}
+	}
+	if !c.Limit.Valid() {
+		return &poker.InvalidInputError{Msg: "unknown betting structure"}
+	}
+	return nil
+}
+
+// Seat is one place at the table. An empty seat has no Player.
+type Seat struct {
+	Player    string       `json:"player,omitempty"`
+	Stack     int64        `json:"stack"`
+	Hole      []poker.Card `json:"hole,omitempty"`
+	Bet       int64        `json:"bet"`       // chips put in on this street
+	Committed int64        `json:"committed"` // chips put in this hand, Bet included
+	InHand    bool         `json:"in_hand"`   // dealt into this hand and not folded
+	AllIn     bool         `json:"all_in"`
+	Acted     bool         `json:"acted"` // has acted since the last full bet or raise
+}
+
+// canAct reports whether the seat still takes part in betting.
+func (s Seat) canAct() bool {
+	return s.InHand && !s.AllIn
+}
+
+// Street is the stage of the hand being played.
+type Street int
+
+const (
+	Waiting  Street = iota // no hand has started
+	Preflop                // hole cards dealt, blinds posted
+	Flop                   // three board cards
+	Turn                   // four board cards
+	River                  // five board cards
+	Complete               // the pot has been awarded; see State.Result
+)
+
+var streetNames = [...]string{"waiting", "preflop", "flop", "turn", "river", "complete"}
+
+func (s Street) String() string {
+	if s < 0 || int(s) >= len(streetNames) {
+		return "unknown"
+	}
+	return streetNames[s]
+}
+
+func (s Street) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Street) UnmarshalText(b []byte) error {
+	for i, name := range streetNames {
+		if name == string(b) {
+			*s = Street(i)
+			return nil
+		}
+	}
+	return &poker.InvalidInputError{Msg: fmt.Sprintf("unknown street %q", b)}
+}
+
+// betting reports whether players act on this street.
+func (s Street) betting() bool {
+	return s >= Preflop && s <= River
+}
+
+// State is a table and the hand in play. Every transition returns a new
+// State and leaves its receiver unchanged.
+type State struct {
+	Config     Config       `json:"config"`
+	Seats      []Seat       `json:"seats"`
+	Button     int          `json:"button"` // dealer seat; -1 before the first hand
+	Street     Street       `json:"street"`
+	Board      []poker.Card `json:"board"`
+	Deck       poker.Deck   `json:"deck"`        // the hand's deck and how far it has dealt
+	ToAct      int          `json:"to_act"`      // seat to act; -1 when nobody is
+	CurrentBet int64        `json:"current_bet"` // the highest Bet this street
+	MinRaise   int64        `json:"min_raise"`   // the last full bet or raise increment
+	Raises     int          `json:"raises"`      // full bets and raises this street, the big blind included
+	HandNumber int          `json:"hand_number"`
+	Result     *Result      `json:"result,omitempty"`  // set once the hand is Complete
+	History    *HandHistory `json:"history,omitempty"` // the current or last hand's record
+}
+
+// NewTable returns an empty table.
+func NewTable(cfg Config) (State, error) {
+	if err := cfg.validate(); err != nil {
+		return State{}, err
+	}
+	return State{
+		Config: cfg,
+		Seats:  make([]Seat, cfg.MaxSeats),
+		Button: -1,
+		ToAct:  -1,
+	}, nil
+}
+
+// clone copies everything a transition may modify.
+func (s State) clone() State {
+	s.Seats = append([]Seat(nil), s.Seats...)
+	s.Board = append([]poker.Card(nil), s.Board...)
+	return s
+}
+
+// InHand reports whether a hand is being played.
+func (s State) InHand() bool {
+	return s.Street.betting()
+}
+
+// Sit puts player in an empty seat with stack chips. Players join and leave
+// between hands.
+func (s State) Sit(seat int, player string, stack int64) (State, error) {
+	if err := s.checkSeat(seat); err != nil {
+		return State{}, err
+	}
+	if player == "" || stack <= 0 {
+		return State{}, &poker.InvalidInputError{Msg: "need a player and a positive stack"}
+	}
+	if s.Seats[seat].Player != "" {
+		return State{}, &InvalidActionError{Msg: fmt.Sprintf("seat %d is taken", seat)}
+	}
+	for _, st := range s.Seats {
+		if st.Player == player {
+			return State{}, &InvalidActionError{Msg: fmt.Sprintf("%s is already seated", player)}
+		}
+	}
+	s = s.clone()
+	s.Seats[seat] = Seat{Player: player, Stack: stack}
+	return s, nil
+}
+
+// Stand empties a seat, returning the stack it held.
+func (s State) Stand(seat int) (State, int64, error) {
+	if err := s.checkSeat(seat); err != nil {
+		return State{}, 0, err
+	}
+	if s.Seats[seat].Player == "" {
+		return State{}, 0, &InvalidActionError{Msg: fmt.Sprintf("seat %d is empty", seat)}
+	}
+	stack := s.Seats[seat].Stack
+	s = s.clone()
+	s.Seats[seat] = Seat{}
+	return s, stack, nil
+}
+
+func (s State) checkSeat(seat int) error {
+	if seat < 0 || seat >= len(s.Seats) {
+		return &poker.InvalidInputError{Msg: fmt.Sprintf("no seat %d", seat)}
+	}
+	if s.InHand() {
+		return &InvalidActionError{Msg: "a hand is in progress"}
+	}
+	return nil
+}
+
+// next returns the first seat clockwise after from that satisfies ok, or -1.
+func (s State) next(from int, ok func(Seat) bool) int {
+	n := len(s.Seats)
+	for i := 1; i <= n; i++ {
+		seat := ((from+i)%n + n) % n
+		if ok(s.Seats[seat]) {
+			return seat
+		}
+	}
+	return -1
+}
+
+// count returns how many seats satisfy ok.
+func (s State) count(ok func(Seat) bool) int {
+	n := 0
+	for _, st := range s.Seats {
+		if ok(st) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,324 @@
+package lobby
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/texas-holdem/backend/internal/deck"
+	"github.com/texas-holdem/backend/internal/game"
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+// Table is a table players can find in the lobby and the game played at it.
+type Table struct {
+	ID       string     `json:"id"`
+	MinBuyIn int64      `json:"min_buy_in"`
+	MaxBuyIn int64      `json:"max_buy_in"`
+	State    game.State `json:"state"`
+
+	// NextSeed is the secret server seed the next hand will be shuffled
+	// with; only its commitment may be shown.
+	NextSeed    string            `json:"next_seed"`
+	ClientSeeds map[string]string `json:"client_seeds,omitempty"` // by player
+	// Shuffle proves the last hand dealt. Its server seed must stay hidden
+	// until the hand is over.
+	Shuffle *deck.Proof `json:"shuffle,omitempty"`
+	// Tokens holds each seated player's secret seat token, which proves
+	// who they are. It is issued when they sit and dropped when they stand.
+	Tokens map[string]string `json:"tokens,omitempty"`
+}
+
+// MaxClientSeed is the longest client seed a player may set, in bytes.
+const MaxClientSeed = 256
+
+// NewTable returns a table with cfg's rules, not yet stored. A zero buy-in
+// range defaults to 20 to 100 big blinds.
+func NewTable(cfg game.Config, minBuyIn, maxBuyIn int64) (Table, error) {
+	state, err := game.NewTable(cfg)
+	if err != nil {
+		return Table{}, err
+	}
+	if minBuyIn == 0 && maxBuyIn == 0 {
+		minBuyIn, maxBuyIn = 20*cfg.BigBlind, 100*cfg.BigBlind
+	}
+	if minBuyIn <= 0 || maxBuyIn < minBuyIn {
+		return Table{}, &poker.InvalidInputError{Msg: "need 0 < min buy-in <= max buy-in"}
+	}
+	seed, err := deck.NewServerSeed()
+	if err != nil {
+		return Table{}, err
+	}
+	return Table{MinBuyIn: minBuyIn, MaxBuyIn: maxBuyIn, State: state, NextSeed: seed}, nil
+}
+
+// Seated returns how many seats are taken.
+func (t Table) Seated() int {
+	n := 0
+	for _, st := range t.State.Seats {
+		if st.Player != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Open reports whether a seat is free.
+func (t Table) Open() bool {
+	return t.Seated() < len(t.State.Seats)
+}
+
+// Sit seats player with a buy-in inside the table's range.
+func (t Table) Sit(seat int, player string, buyIn int64) (Table, error) {
+	if buyIn < t.MinBuyIn || buyIn > t.MaxBuyIn {
+		return Table{}, &poker.InvalidInputError{Msg: fmt.Sprintf("buy-in must be %d-%d", t.MinBuyIn, t.MaxBuyIn)}
+	}
+	state, err := t.State.Sit(seat, player, buyIn)
+	if err != nil {
+		return Table{}, err
+	}
+	token, err := newToken()
+	if err != nil {
+		return Table{}, err
+	}
+	t.State, t.Tokens = state, withKey(t.Tokens, player, token)
+	return t, nil
+}
+
+// Stand unseats the player in seat, dropping their token and client seed,
+// and returns the chips they leave with.
+func (t Table) Stand(seat int) (Table, int64, error) {
+	if seat >= 0 && seat < len(t.State.Seats) {
+		player := t.State.Seats[seat].Player
+		t.Tokens = withoutKey(t.Tokens, player)
+		t.ClientSeeds = withoutKey(t.ClientSeeds, player)
+	}
+	state, stack, err := t.State.Stand(seat)
+	if err != nil {
+		return Table{}, 0, err
+	}
+	t.State = state
+	return t, stack, nil
+}
+
+// Authorize checks that token is seated player's seat token.
+func (t Table) Authorize(player, token string) error {
+	want, ok := t.Tokens[player]
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+		return &UnauthorizedError{Player: player}
+	}
+	return nil
+}
+
+// SetClientSeed sets the seed player adds to the shuffles of the hands they
+// are seated for.
+func (t Table) SetClientSeed(player, seed string) (Table, error) {
+	if len(seed) > MaxClientSeed {
+		return Table{}, &poker.InvalidInputError{Msg: fmt.Sprintf("client seed is longer than %d bytes", MaxClientSeed)}
+	}
+	t.ClientSeeds = withKey(t.ClientSeeds, player, seed)
+	return t, nil
+}
+
+// Deal starts the next hand with a deck shuffled by NextSeed and the client
+// seeds of the players dealt in, those seated with chips, and draws a new
+// server seed for the hand after.
+func (t Table) Deal() (Table, error) {
+	proof := deck.Proof{
+		Commitment: deck.Commit(t.NextSeed),
+		ServerSeed: t.NextSeed,
+		Nonce:      t.State.HandNumber + 1,
+	}
+	for _, st := range t.State.Seats {
+		if st.Player != "" && st.Stack > 0 {
+			proof.ClientSeeds = append(proof.ClientSeeds, t.ClientSeeds[st.Player])
+		}
+	}
+	state, err := t.State.StartHand(proof.Deck(t.State.Config.Variant))
+	if err != nil {
+		return Table{}, err
+	}
+	next, err := deck.NewServerSeed()
+	if err != nil {
+		return Table{}, err
+	}
+	t.State, t.NextSeed, t.Shuffle = state, next, &proof
+	return t, nil
+}
+
+// Store keeps the tables. Implementations must be safe for concurrent use;
+// MemoryStore keeps them in process memory, and a shared store would let
+// several backend replicas serve the same tables.
+type Store interface {
+	// Create stores t under a new ID and returns it with the ID set.
+	Create(t Table) (Table, error)
+	Get(id string) (Table, error)
+	// List returns every table, in creation order.
+	List() ([]Table, error)
+	// Update replaces a table with what fn makes of it, atomically with
+	// respect to other updates. Nothing is stored if fn fails.
+	Update(id string, fn func(Table) (Table, error)) (Table, error)
+
+	// SaveHand stores a finished hand's history under its ID. A hand stored
+	// for the first time gets the next Number, counting hands across every
+	// table from 1; saving it again keeps its Number.
+	SaveHand(h game.HandHistory) error
+	Hand(id string) (game.HandHistory, error)
+}
+
+// NotFoundError reports a table or hand ID the store does not hold.
+type NotFoundError struct {
+	Kind string // "table" or "hand"
+	ID   string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("no %s %q", e.Kind, e.ID)
+}
+
+func IsNotFound(err error) bool {
+	_, ok := err.(*NotFoundError)
+	return ok
+}
+
+// UnauthorizedError reports a seat token that does not match a seated
+// player's.
+type UnauthorizedError struct {
+	Player string
+}
+
+func (e *UnauthorizedError) Error() string {
+	return fmt.Sprintf("not authorized as %s: wrong or missing seat token", e.Player)
+}
+
+func IsUnauthorized(err error) bool {
+	_, ok := err.(*UnauthorizedError)
+	return ok
+}
+
+// newToken returns a random seat token: 16 bytes as 32 hex digits.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// withKey returns a copy of m with k set to v, leaving m to the tables
+// that share it.
+func withKey(m map[string]string, k, v string) map[string]string {
+	out := make(map[string]string, len(m)+1)
+	for key, val := range m {
+		out[key] = val
+	}
+	out[k] = v
+	return out
+}
+
+// withoutKey returns a copy of m without k.
+func withoutKey(m map[string]string, k string) map[string]string {
+	if _, ok := m[k]; !ok {
+		return m
+	}
+	out := make(map[string]string, len(m))
+	for key, val := range m {
+		if key != k {
+			out[key] = val
+		}
+	}
+	return out
+}
+
+// MemoryStore is a Store in process memory, numbering tables t1, t2, ...
+type MemoryStore struct {
+	mu     sync.Mutex
+	tables map[string]Table
+	hands  map[string]game.HandHistory
+	nextID int
+	saved  int64 // hands stored, numbering the next
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{tables: make(map[string]Table), hands: make(map[string]game.HandHistory)}
+}
+
+func (m *MemoryStore) Create(t Table) (Table, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	t.ID = "t" + strconv.Itoa(m.nextID)
+	m.tables[t.ID] = t
+	return t, nil
+}
+
+func (m *MemoryStore) Get(id string) (Table, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tables[id]
+	if !ok {
+		return Table{}, &NotFoundError{Kind: "table", ID: id}
+	}
+	return t, nil
+}
+
+func (m *MemoryStore) List() ([]Table, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tables := make([]Table, 0, len(m.tables))
+	for _, t := range m.tables {
+		tables = append(tables, t)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		a, _ := strconv.Atoi(tables[i].ID[1:])
+		b, _ := strconv.Atoi(tables[j].ID[1:])
+		return a < b
+	})
+	return tables, nil
+}
+
+func (m *MemoryStore) Update(id string, fn func(Table) (Table, error)) (Table, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tables[id]
+	if !ok {
+		return Table{}, &NotFoundError{Kind: "table", ID: id}
+	}
+	t, err := fn(t)
+	if err != nil {
+		return Table{}, err
+	}
+	t.ID = id
+	m.tables[id] = t
+	return t, nil
+}
+
+func (m *MemoryStore) SaveHand(h game.HandHistory) error {
+	if h.ID == "" {
+		return &poker.InvalidInputError{Msg: "hand history has no ID"}
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if old, ok := m.hands[h.ID]; ok {
+		h.Number = old.Number
+	} else {
+		m.saved++
+		h.Number = m.saved
+	}
+	m.hands[h.ID] = h
+	return nil
+}
+
+func (m *MemoryStore) Hand(id string) (game.HandHistory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.hands[id]
+	if !ok {
+		return game.HandHistory{}, &NotFoundError{Kind: "hand", ID: id}
+	}
+	return h, nil
+}
@@ -0,0 +1,168 @@
+package lobby
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/texas-holdem/backend/internal/deck"
+	"github.com/texas-holdem/backend/internal/game"
+	"github.com/texas-holdem/backend/internal/poker"
+)
+
+func TestMemoryStore(t *testing.T) {
+	m := NewMemoryStore()
+	table, err := NewTable(game.Config{SmallBlind: 1, BigBlind: 2, MaxSeats: 2}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.MinBuyIn != 40 || table.MaxBuyIn != 200 {
+		t.Errorf("default buy-in %d-%d, want 40-200", table.MinBuyIn, table.MaxBuyIn)
+	}
+	for i := 0; i < 10; i++ {
+		m.Create(table)
+	}
+	if list, _ := m.List(); len(list) != 10 || list[1].ID != "t2" || list[9].ID != "t10" {
+		t.Errorf("list out of order: %v", list)
+	}
+
+	sit := func(player string, seat int) func(Table) (Table, error) {
+		return func(t Table) (Table, error) { return t.Sit(seat, player, 100) }
+	}
+	if _, err := m.Update("t1", sit("alice", 0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Update("t1", sit("bob", 0)); !game.IsInvalidAction(err) {
+		t.Errorf("taken seat: err = %v", err)
+	}
+	if _, err := m.Update("t1", func(t Table) (Table, error) { return t.Sit(1, "bob", 1) }); !poker.IsInvalidInput(err) {
+		t.Errorf("short buy-in: err = %v", err)
+	}
+	got, _ := m.Get("t1")
+	if got.Seated() != 1 || !got.Open() {
+		t.Errorf("failed updates were stored: %+v", got.State.Seats)
+	}
+	if _, err := m.Update("t1", sit("bob", 1)); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = m.Get("t1"); got.Open() {
+		t.Error("full table is open")
+	}
+	if _, err := m.Get("t99"); !IsNotFound(err) {
+		t.Errorf("Get(t99): err = %v, want not found", err)
+	}
+
+	// Hands are numbered across tables, and keep their number when saved
+	// again.
+	for _, id := range []string{"t1-1", "t2-1", "t1-1"} {
+		if err := m.SaveHand(game.HandHistory{ID: id, HandNumber: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, _ := m.Hand("t1-1")
+	second, _ := m.Hand("t2-1")
+	if first.Number != 1 || second.Number != 2 {
+		t.Errorf("hand numbers %d and %d, want 1 and 2", first.Number, second.Number)
+	}
+}
+
+func TestTable_Deal(t *testing.T) {
+	table, err := NewTable(game.Config{SmallBlind: 1, BigBlind: 2, MaxSeats: 3}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, _ = table.Sit(0, "alice", 100)
+	table, _ = table.Sit(2, "bob", 100)
+	if table, err = table.SetClientSeed("bob", "lucky"); err != nil {
+		t.Fatal(err)
+	}
+	// Carol has no chips, so she is not dealt in and her seed is not used.
+	table.State.Seats[1] = game.Seat{Player: "carol"}
+	table, _ = table.SetClientSeed("carol", "broke")
+	if _, err := table.SetClientSeed("bob", strings.Repeat("x", MaxClientSeed+1)); !poker.IsInvalidInput(err) {
+		t.Errorf("long client seed: err = %v", err)
+	}
+
+	seed := table.NextSeed
+	dealt, err := table.Deal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := dealt.Shuffle
+	if p == nil || p.ServerSeed != seed || p.Commitment != deck.Commit(seed) || !reflect.DeepEqual(p.ClientSeeds, []string{"", "lucky"}) || p.Nonce != 1 {
+		t.Fatalf("proof = %+v", p)
+	}
+	if dealt.NextSeed == seed || dealt.NextSeed == "" {
+		t.Error("server seed was not replaced")
+	}
+	if ok, err := dealt.State.History.DealtFrom(p.Deck(poker.Holdem)); !ok || err != nil {
+		t.Errorf("hand was not dealt from the proof's deck: %v", err)
+	}
+}
+
+func TestTable_JSONMidHand(t *testing.T) {
+	table, err := NewTable(game.Config{SmallBlind: 1, BigBlind: 2, MaxSeats: 2}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table, _ = table.Sit(0, "alice", 100)
+	table, _ = table.Sit(1, "bob", 100)
+	if table, err = table.Deal(); err != nil {
+		t.Fatal(err)
+	}
+	if table.State, err = table.State.Apply(game.Action{Seat: 0, Type: game.Call}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(table)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored Table
+	if err := json.Unmarshal(b, &stored); err != nil {
+		t.Fatal(err)
+	}
+	want, err := table.State.Apply(game.Action{Seat: 1, Type: game.Check})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := stored.State.Apply(game.Action{Seat: 1, Type: game.Check})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Street != game.Flop || !reflect.DeepEqual(got.Board, want.Board) || got.Deck.Remaining() != want.Deck.Remaining() {
+		t.Errorf("after a JSON round trip the flop is %v with %d cards left, want %v with %d", got.Board, got.Deck.Remaining(), want.Board, want.Deck.Remaining())
+	}
+	if !reflect.DeepEqual(got.History, want.History) {
+		t.Errorf("history = %+v, want %+v", got.History, want.History)
+	}
+}
+
+func TestTable_Tokens(t *testing.T) {
+	table, err := NewTable(game.Config{SmallBlind: 1, BigBlind: 2, MaxSeats: 2}, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seated, _ := table.Sit(0, "alice", 100)
+	token := seated.Tokens["alice"]
+	if len(token) != 32 || table.Tokens != nil {
+		t.Fatalf("token %q, earlier table's tokens %v", token, table.Tokens)
+	}
+	if err := seated.Authorize("alice", token); err != nil {
+		t.Error(err)
+	}
+	for _, bad := range []string{"", "alice", token[1:]} {
+		if err := seated.Authorize("alice", bad); !IsUnauthorized(err) {
+			t.Errorf("token %q: err = %v", bad, err)
+		}
+	}
+	seated, _ = seated.SetClientSeed("alice", "lucky")
+	stood, stack, err := seated.Stand(0)
+	if err != nil || stack != 100 {
+		t.Fatalf("stand: %d %v", stack, err)
+	}
+	if _, ok := stood.Tokens["alice"]; ok || stood.ClientSeeds["alice"] != "" || seated.Tokens["alice"] != token {
+		t.Errorf("after standing: tokens %v seeds %v", stood.Tokens, stood.ClientSeeds)
+	}
+}
@@ -70,20 +70,34 @@ func (c Card) String() string {
 	return string([]byte{c.Suit, rankToChar[c.Rank]})
 }
 
+// MarshalText writes the card as its 2-char string, so cards appear in JSON
+// as e.g. "HA".
+func (c Card) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c *Card) UnmarshalText(b []byte) error {
+	v, err := ParseCard(string(b))
+	if err != nil {
+		return err
+	}
+	*c = v
+	return nil
+}
+
 // ParseCards parses multiple cards.
 func ParseCards(strs []string) ([]Card, error) {
-	seen := make(map[string]bool)
+	var seen CardSet
 	cards := make([]Card, 0, len(strs))
 	for _, s := range strs {
 		c, err := ParseCard(s)
 		if err != nil {
 			return nil, err
 		}
-		key := c.String()
-		if seen[key] {
-			return nil, fmt.Errorf("duplicate card: %s", key)
+		if seen.Contains(c) {
+			return nil, &DuplicateCardError{Card: c.String()}
 		}
-		seen[key] = true
+		seen.Add(c)
 		cards = append(cards, c)
 	}
 	return cards, nil
@@ -0,0 +1,107 @@
+package poker
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// NumCards is the size of a standard deck.
+const NumCards = 52
+
+var indexToSuit = [4]byte{SuitHearts, SuitDiamonds, SuitClubs, SuitSpades}
+
+// Index returns the card's compact encoding 0-51: suit-major in H, D, C, S
+// order, then rank 2 through Ace. Card{} and other invalid cards return -1.
+func (c Card) Index() int {
+	if c.Rank < Rank2 || c.Rank > RankA {
+		return -1
+	}
+	switch c.Suit {
+	case SuitHearts:
+		return c.Rank - Rank2
+	case SuitDiamonds:
+		return numRanks + c.Rank - Rank2
+	case SuitClubs:
+		return 2*numRanks + c.Rank - Rank2
+	case SuitSpades:
+		return 3*numRanks + c.Rank - Rank2
+	}
+	return -1
+}
+
+// CardAt is the inverse of Card.Index.
+func CardAt(i int) Card {
+	return Card{Suit: indexToSuit[i/numRanks], Rank: Rank2 + i%numRanks}
+}
+
+// CardSet is a set of cards as a bitset over Card.Index.
+type CardSet uint64
+
+// fullDeck holds all 52 cards.
+const fullDeck CardSet = 1<<NumCards - 1
+
+// CardSetOf returns the set of the given cards.
+func CardSetOf(cards []Card) CardSet {
+	var s CardSet
+	for _, c := range cards {
+		s.Add(c)
+	}
+	return s
+}
+
+func cardBit(c Card) CardSet {
+	return 1 << uint(c.Index())
+}
+
+// Add puts c in the set.
+func (s *CardSet) Add(c Card) {
+	*s |= cardBit(c)
+}
+
+// Remove takes c out of the set.
+func (s *CardSet) Remove(c Card) {
+	*s &^= cardBit(c)
+}
+
+// Contains reports whether c is in the set.
+func (s CardSet) Contains(c Card) bool {
+	return s&cardBit(c) != 0
+}
+
+// Count returns the number of cards in the set.
+func (s CardSet) Count() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// Each calls fn for every card in index order.
+func (s CardSet) Each(fn func(Card)) {
+	for ; s != 0; s &= s - 1 {
+		fn(CardAt(bits.TrailingZeros64(uint64(s))))
+	}
+}
+
+// Cards returns the cards in index order.
+func (s CardSet) Cards() []Card {
+	cards := make([]Card, 0, s.Count())
+	s.Each(func(c Card) { cards = append(cards, c) })
+	return cards
+}
+
+// String lists the cards, e.g. "[HA HK]".
+func (s CardSet) String() string {
+	var b strings.Builder
+	b.WriteByte('[')
+	s.Each(func(c Card) {
+		if b.Len() > 1 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(c.String())
+	})
+	b.WriteByte(']')
+	return b.String()
+}
+
+// suitMask returns the 13-bit rank mask of suit i (bit r = rank r+2).
+func (s CardSet) suitMask(i int) uint16 {
+	return uint16(s>>(uint(i)*numRanks)) & (1<<numRanks - 1)
+}
@@ -0,0 +1,99 @@
+package poker
+
+import "fmt"
+
+// RNG is the randomness a Deck shuffles with; *rand.Rand is one.
+type RNG interface {
+	Intn(n int) int
+}
+
+// Deck is a pile of cards dealt from the top. Copying a Deck copies its
+// position but shares its cards: a copy may deal on its own, but only a
+// deck no copy deals from may be shuffled.
+type Deck struct {
+	Cards []Card `json:"cards"` // in dealing order
+	Next  int    `json:"next"`  // Cards[Next:] are left
+}
+
+// NewDeck returns variant's deck without the excluded cards, in index order.
+func NewDeck(variant Variant, exclude ...Card) *Deck {
+	return &Deck{Cards: (variant.Deck() &^ CardSetOf(exclude)).Cards()}
+}
+
+// DeckOf returns a deck that deals cards in the order given.
+func DeckOf(cards []Card) *Deck {
+	return &Deck{Cards: append([]Card(nil), cards...)}
+}
+
+// DeckError reports a deal the deck has too few cards left for.
+type DeckError struct {
+	Need, Left int
+}
+
+func (e *DeckError) Error() string {
+	return fmt.Sprintf("deck has %d cards left, need %d", e.Left, e.Need)
+}
+
+// Shuffle puts the cards left in a uniformly random order: for i from the
+// last card down to 1 it swaps card i with card rng.Intn(i+1).
+func (d *Deck) Shuffle(rng RNG) {
+	rest := d.Cards[d.Next:]
+	for i := len(rest) - 1; i > 0; i-- {
+		j := rng.Intn(i + 1)
+		rest[i], rest[j] = rest[j], rest[i]
+	}
+}
+
+// Deal takes the next n cards off the deck.
+func (d *Deck) Deal(n int) ([]Card, error) {
+	cards, err := d.Peek(n)
+	if err != nil {
+		return nil, err
+	}
+	d.Next += n
+	return cards, nil
+}
+
+// Burn discards the next card.
+func (d *Deck) Burn() error {
+	_, err := d.Deal(1)
+	return err
+}
+
+// Peek returns the next n cards without dealing them.
+func (d *Deck) Peek(n int) ([]Card, error) {
+	if n < 0 || n > d.Remaining() {
+		return nil, &DeckError{Need: n, Left: d.Remaining()}
+	}
+	return d.Cards[d.Next : d.Next+n : d.Next+n], nil
+}
+
+// Remaining returns how many cards are left.
+func (d *Deck) Remaining() int {
+	return len(d.Cards) - d.Next
+}
+
+// Reset puts every dealt card back, on top in the order dealt.
+func (d *Deck) Reset() {
+	d.Next = 0
+}
+
+// dealRandom deals n cards picked uniformly at random from those left,
+// passing over cards in skip. It shuffles only as far as it must, which
+// makes it the simulators' cheap stand-in for Shuffle and Deal.
+func (d *Deck) dealRandom(rng RNG, n int, skip CardSet) ([]Card, error) {
+	rest := d.Cards[d.Next:]
+	k := 0
+	for i := 0; k < n && i < len(rest); i++ {
+		j := i + rng.Intn(len(rest)-i)
+		rest[i], rest[j] = rest[j], rest[i]
+		if !skip.Contains(rest[i]) {
+			rest[k], rest[i] = rest[i], rest[k]
+			k++
+		}
+	}
+	if k < n {
+		return nil, &DeckError{Need: n, Left: k}
+	}
+	return d.Deal(n)
+}
@@ -0,0 +1,71 @@
+package poker
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDeck_DealBurnPeek(t *testing.T) {
+	known, _ := ParseCards([]string{"HA", "SA", "D2"})
+	d := NewDeck(Holdem, known...)
+	if d.Remaining() != 49 {
+		t.Fatalf("Remaining() = %d, want 49", d.Remaining())
+	}
+	d.Shuffle(rand.New(rand.NewSource(1)))
+
+	next, _ := d.Peek(3)
+	if d.Remaining() != 49 {
+		t.Error("Peek dealt cards")
+	}
+	if err := d.Burn(); err != nil {
+		t.Fatal(err)
+	}
+	dealt, err := d.Deal(2)
+	if err != nil || !reflect.DeepEqual(dealt, next[1:]) {
+		t.Errorf("Deal(2) = %v, %v; want %v after the burn", dealt, err, next[1:])
+	}
+
+	rest, _ := d.Deal(d.Remaining())
+	all := CardSetOf(append(append(next[:1:1], dealt...), rest...))
+	if all.Count() != 49 || all&CardSetOf(known) != 0 {
+		t.Errorf("dealt %v, want all 49 unknown cards once each", all)
+	}
+}
+
+func TestDeck_Exhausted(t *testing.T) {
+	d := NewDeck(ShortDeck)
+	if _, err := d.Deal(37); err == nil {
+		t.Fatal("dealt 37 cards from a short deck")
+	}
+	if d.Remaining() != 36 {
+		t.Errorf("failed deal took cards: %d left", d.Remaining())
+	}
+	d.Deal(35)
+	if err := d.Burn(); err != nil {
+		t.Fatal(err)
+	}
+	var deckErr *DeckError
+	if err := d.Burn(); err == nil || !errors.As(err, &deckErr) || deckErr.Left != 0 || deckErr.Need != 1 {
+		t.Errorf("burn from an empty deck: err = %v", err)
+	}
+	if _, err := d.Peek(1); err == nil {
+		t.Error("peeked into an empty deck")
+	}
+
+	d.Reset()
+	if d.Remaining() != 36 {
+		t.Errorf("after Reset: %d left", d.Remaining())
+	}
+}
+
+func TestDeck_CopiesDealApart(t *testing.T) {
+	d := *DeckOf(Holdem.Deck().Cards())
+	copied := d
+	a, _ := d.Deal(2)
+	b, _ := copied.Deal(2)
+	if !reflect.DeepEqual(a, b) || d.Remaining() != 50 || copied.Remaining() != 50 {
+		t.Errorf("copies dealt %v and %v", a, b)
+	}
+}
@@ -0,0 +1,269 @@
+package poker
+
+import (
+	"fmt"
+	"math"
+)
+
+// EquityResult summarises how a hand fares against the field, either over
+// every possible deal (Exact) or over Samples random ones. Standard errors
+// are zero for exact results.
+type EquityResult struct {
+	Win    float64 `json:"win"`    // fraction of deals won outright, scooping both halves in hi-lo
+	Tie    float64 `json:"tie"`    // fraction of deals where we share the pot
+	Loss   float64 `json:"loss"`   // fraction of deals lost
+	Equity float64 `json:"equity"` // expected pot share; a k-way split counts 1/k
+
+	WinStdErr    float64 `json:"win_std_err"`
+	TieStdErr    float64 `json:"tie_std_err"`
+	LossStdErr   float64 `json:"loss_std_err"`
+	EquityStdErr float64 `json:"equity_std_err"`
+
+	Samples int64 `json:"samples"` // simulations run, or deals enumerated if Exact
+	Exact   bool  `json:"exact"`
+	Seed    int64 `json:"seed,omitempty"` // replays a simulation via SimConfig.Seed
+
+	// Ranks breaks the deals down by the category the hand finished as,
+	// High Card through Royal Flush. Simulate and Enumerate also fill in
+	// OpponentRanks, pooling every opponent's hands.
+	Ranks         []RankStat `json:"ranks,omitempty"`
+	OpponentRanks []RankStat `json:"opponent_ranks,omitempty"`
+}
+
+// RankStat is how often a hand finished as one category, and how it fared
+// when it did. In hi-lo the category is the high hand's.
+type RankStat struct {
+	Rank        RankType `json:"rank"`
+	RankName    string   `json:"rank_name"`
+	Probability float64  `json:"probability"`
+	Win         float64  `json:"win"`    // given the category
+	Equity      float64  `json:"equity"` // given the category
+}
+
+// EquityConfig describes an all-in equity calculation between players who
+// each hold a known hand, a range, or random cards.
+type EquityConfig struct {
+	Variant   Variant // Hold'em unless set
+	Players   []Range // 2-10; a known hand is a one-combo range, nil deals random cards
+	Community []Card  // 0-5 known community cards
+	Dead      []Card  // cards out of play, e.g. burned or mucked
+	NumSims   int     // Monte Carlo deals, 1-MaxSims; unused when enumerating
+	Workers   int     // goroutines to shard across; 0 means GOMAXPROCS
+	Seed      int64   // 0 picks a random seed; results report the seed used
+}
+
+func (cfg EquityConfig) validate() error {
+	if len(cfg.Players) < 2 || len(cfg.Players) > 10 {
+		return &InvalidInputError{Msg: "need 2-10 players"}
+	}
+	if len(cfg.Community) > 5 {
+		return &InvalidInputError{Msg: "max 5 community cards"}
+	}
+	if cfg.Workers < 0 {
+		return &InvalidInputError{Msg: "workers must not be negative"}
+	}
+	if !cfg.Variant.Valid() {
+		return &InvalidInputError{Msg: "unknown variant"}
+	}
+	var seen CardSet
+	known := append(append([]Card{}, cfg.Community...), cfg.Dead...)
+	if err := cfg.Variant.checkDeck(known); err != nil {
+		return err
+	}
+	for _, c := range known {
+		if seen.Contains(c) {
+			return &DuplicateCardError{Card: c.String()}
+		}
+		seen.Add(c)
+	}
+	holeCards := cfg.Variant.HoleCards()
+	if cfg.known().Count()+5-len(cfg.Community)+holeCards*len(cfg.Players) > NumCards {
+		return &InvalidInputError{Msg: "not enough cards to deal every player"}
+	}
+	for i, r := range cfg.Players {
+		for _, c := range r {
+			if len(c.Cards) != holeCards {
+				return &InvalidInputError{Msg: fmt.Sprintf("player %d: need exactly %d hole cards", i+1, holeCards)}
+			}
+		}
+		switch {
+		case len(r) == 1:
+			// A known hand must not share cards with anything else known.
+			if err := cfg.Variant.checkDeck(r[0].Cards); err != nil {
+				return err
+			}
+			for _, c := range r[0].Cards {
+				if seen.Contains(c) {
+					return &DuplicateCardError{Card: c.String()}
+				}
+				seen.Add(c)
+			}
+		case r != nil && len(r.Without(cfg.known())) == 0:
+			return &InvalidInputError{Msg: fmt.Sprintf("player %d: no combo in range is still live", i+1)}
+		}
+	}
+	return nil
+}
+
+// known returns the community and dead cards, and the cards the variant's
+// deck leaves out.
+func (cfg EquityConfig) known() CardSet {
+	return CardSetOf(cfg.Community) | CardSetOf(cfg.Dead) | fullDeck&^cfg.Variant.Deck()
+}
+
+// recordDeal settles one deal for every player from their hand values and,
+// in hi-lo variants, their lows (nil otherwise), and adds it to their tallies
+// with the given weight.
+func recordDeal(tallies []equityTally, values []HandValue, lows []LowValue, weight float64) {
+	best, winners := HandValue(0), 0
+	for _, v := range values {
+		switch {
+		case v > best:
+			best, winners = v, 1
+		case v == best:
+			winners++
+		}
+	}
+	bestLow, lowWinners := LowValue(0), 0
+	for _, l := range lows {
+		switch l.Compare(bestLow) {
+		case 1:
+			bestLow, lowWinners = l, 1
+		case 0:
+			if l != 0 {
+				lowWinners++
+			}
+		}
+	}
+	high := potUnits
+	if lowWinners > 0 {
+		high /= 2
+	}
+	for i, v := range values {
+		units := 0
+		if v == best {
+			units += high / winners
+		}
+		if lowWinners > 0 && lows[i] == bestLow {
+			units += potUnits / 2 / lowWinners
+		}
+		tallies[i].record(units, v.Rank(), weight)
+	}
+}
+
+// potUnits divides a pot so that every share, a k-way split of the whole pot
+// or of either half for up to 10 players, is a whole number of units.
+const potUnits = 5040
+
+// equityTally accumulates the weighted outcomes of deals for an
+// EquityResult. Monte Carlo deals all weigh 1. Pot shares are kept in
+// potUnits, so unit weights sum exactly.
+type equityTally struct {
+	deals               int64
+	wins, ties, losses  float64 // weight of deals scooped, shared and lost
+	share, shareSquares float64 // weighted pot share in potUnits, and its square
+	ranks               [RoyalFlush + 1]rankTally
+}
+
+// rankTally is the part of an equityTally where the hand finished as one
+// category.
+type rankTally struct {
+	weight, wins, share float64
+}
+
+// record adds a deal in which our hand finished as rank and won units of
+// the pot.
+func (t *equityTally) record(units int, rank RankType, weight float64) {
+	t.deals++
+	rt := &t.ranks[rank]
+	switch units {
+	case potUnits:
+		t.wins += weight
+		rt.wins += weight
+	case 0:
+		t.losses += weight
+	default:
+		t.ties += weight
+	}
+	u := float64(units)
+	t.share += weight * u
+	t.shareSquares += weight * u * u
+	rt.weight += weight
+	rt.share += weight * u
+}
+
+func (t *equityTally) add(o equityTally) {
+	t.deals += o.deals
+	t.wins += o.wins
+	t.ties += o.ties
+	t.losses += o.losses
+	t.share += o.share
+	t.shareSquares += o.shareSquares
+	for r := range t.ranks {
+		t.ranks[r].weight += o.ranks[r].weight
+		t.ranks[r].wins += o.ranks[r].wins
+		t.ranks[r].share += o.ranks[r].share
+	}
+}
+
+func (t equityTally) result(exact bool) EquityResult {
+	total := t.wins + t.ties + t.losses
+	if total == 0 {
+		return EquityResult{Exact: exact}
+	}
+	r := EquityResult{
+		Win:     t.wins / total,
+		Tie:     t.ties / total,
+		Loss:    t.losses / total,
+		Equity:  t.share / (total * potUnits),
+		Samples: t.deals,
+		Exact:   exact,
+	}
+	for rank := HighCard; rank <= RoyalFlush; rank++ {
+		rt := t.ranks[rank]
+		stat := RankStat{Rank: rank, RankName: rank.String(), Probability: rt.weight / total}
+		if rt.weight > 0 {
+			stat.Win = rt.wins / rt.weight
+			stat.Equity = rt.share / (rt.weight * potUnits)
+		}
+		r.Ranks = append(r.Ranks, stat)
+	}
+	if !exact && t.deals > 1 {
+		n := float64(t.deals)
+		r.WinStdErr = proportionStdErr(r.Win, n)
+		r.TieStdErr = proportionStdErr(r.Tie, n)
+		r.LossStdErr = proportionStdErr(r.Loss, n)
+		variance := (t.shareSquares/(total*potUnits*potUnits) - r.Equity*r.Equity) * n / (n - 1)
+		r.EquityStdErr = math.Sqrt(math.Max(variance, 0) / n)
+	}
+	return r
+}
+
+// poolRanks merges the category breakdowns of players who saw the same
+// number of deals.
+func poolRanks(results []EquityResult) []RankStat {
+	if len(results) == 0 || results[0].Ranks == nil {
+		return nil
+	}
+	pooled := make([]RankStat, len(results[0].Ranks))
+	for i := range pooled {
+		stat := &pooled[i]
+		*stat = RankStat{Rank: results[0].Ranks[i].Rank, RankName: results[0].Ranks[i].RankName}
+		for _, r := range results {
+			s := r.Ranks[i]
+			stat.Probability += s.Probability
+			stat.Win += s.Probability * s.Win
+			stat.Equity += s.Probability * s.Equity
+		}
+		if stat.Probability > 0 {
+			stat.Win /= stat.Probability
+			stat.Equity /= stat.Probability
+		}
+		stat.Probability /= float64(len(results))
+	}
+	return pooled
+}
+
+func proportionStdErr(p, n float64) float64 {
+	return math.Sqrt(p * (1 - p) / n)
+}
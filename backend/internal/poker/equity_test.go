@@ -0,0 +1,117 @@
+package poker
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestEquity_HandVsHandOnFlop(t *testing.T) {
+	ak, _ := ParseCards([]string{"HA", "HK"})
+	qq, _ := ParseCards([]string{"SQ", "DQ"})
+	flop, _ := ParseCards([]string{"H7", "C2", "HJ"})
+	cfg := EquityConfig{
+		Players:   []Range{HandRange(ak[0], ak[1]), HandRange(qq[0], qq[1])},
+		Community: flop,
+		NumSims:   100000,
+		Seed:      3,
+	}
+	if got, want := ExactDeals(cfg), int64(990); got != want {
+		t.Errorf("ExactDeals = %d, want %d", got, want)
+	}
+	exact, err := EnumerateEquity(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exact[0].Samples != 990 {
+		t.Errorf("enumerated %d runouts, want 990", exact[0].Samples)
+	}
+	if sum := exact[0].Equity + exact[1].Equity; math.Abs(sum-1) > 1e-12 {
+		t.Errorf("equities sum to %v, want 1", sum)
+	}
+	// Nut flush draw plus two overs against an overpair is close to a coin flip.
+	if exact[0].Equity < 0.45 || exact[0].Equity > 0.60 {
+		t.Errorf("AhKh vs QsQd on 7h2cJh: equity %.4f, want about 0.5", exact[0].Equity)
+	}
+
+	mc, err := SimulateEquity(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for p := range mc {
+		if d := math.Abs(mc[p].Equity - exact[p].Equity); d > 4*mc[p].EquityStdErr {
+			t.Errorf("player %d: Monte Carlo %.4f vs exact %.4f", p, mc[p].Equity, exact[p].Equity)
+		}
+	}
+}
+
+func TestEquity_RangeVsRangeExact(t *testing.T) {
+	aces, _ := ParseRange("AA")
+	kings, _ := ParseRange("KK:0.5, QQ")
+	board, _ := ParseCards([]string{"S2", "D7", "C9", "HT"})
+	res, err := EnumerateEquity(context.Background(), EquityConfig{
+		Players:   []Range{aces, kings},
+		Community: board,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Kings and queens both need one of 2 outs on the river.
+	want := 1 - 2.0/44
+	if math.Abs(res[0].Equity-want) > 1e-9 {
+		t.Errorf("AA equity = %.6f, want %.6f", res[0].Equity, want)
+	}
+}
+
+func TestEquity_Validation(t *testing.T) {
+	ak, _ := ParseCards([]string{"HA", "HK"})
+	hand := HandRange(ak[0], ak[1])
+	dead, _ := ParseCards([]string{"HA"})
+	tests := []EquityConfig{
+		{Players: []Range{hand}},
+		{Players: []Range{hand, hand}},
+		{Players: []Range{hand, nil}, Dead: dead},
+	}
+	for i, cfg := range tests {
+		cfg.NumSims = 100
+		if _, err := SimulateEquity(context.Background(), cfg); err == nil {
+			t.Errorf("case %d: want error", i)
+		}
+	}
+}
+
+func TestEnumerate_Ranks(t *testing.T) {
+	hole, _ := ParseCards([]string{"SA", "SK"})
+	turn, _ := ParseCards([]string{"S7", "S2", "D9", "CT"})
+	res, err := Enumerate(context.Background(), SimConfig{Hole: hole, Community: turn, NumPlayers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Nine spades make the flush; sixteen other cards pair our hand or the
+	// board.
+	want := map[RankType]float64{HighCard: 21.0 / 46, OnePair: 16.0 / 46, Flush: 9.0 / 46}
+	var win float64
+	for _, st := range res.Ranks {
+		if math.Abs(st.Probability-want[st.Rank]) > 1e-9 {
+			t.Errorf("%s: probability %v, want %v", st.RankName, st.Probability, want[st.Rank])
+		}
+		win += st.Probability * st.Win
+	}
+	if len(res.Ranks) != 10 || res.Ranks[0].Rank != HighCard || res.Ranks[9].Rank != RoyalFlush {
+		t.Errorf("ranks = %+v", res.Ranks)
+	}
+	if math.Abs(win-res.Win) > 1e-9 {
+		t.Errorf("win by rank adds up to %v, want %v", win, res.Win)
+	}
+	if flush := res.Ranks[Flush-1]; flush.Win < 0.9 || flush.Equity < flush.Win {
+		t.Errorf("nut flush: %+v", flush)
+	}
+
+	var opp float64
+	for _, st := range res.OpponentRanks {
+		opp += st.Probability
+	}
+	if math.Abs(opp-1) > 1e-9 || res.OpponentRanks[HighCard-1].Probability == 0 {
+		t.Errorf("opponent ranks = %+v", res.OpponentRanks)
+	}
+}
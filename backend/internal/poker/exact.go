@@ -0,0 +1,186 @@
+package poker
+
+import (
+	"context"
+	"math"
+)
+
+// ExactEquity walks every remaining board and every opponent holding and
+// returns the exact win/tie/loss fractions and pot equity. See
+// SimConfig.ExactDeals for the cost.
+func ExactEquity(hole []Card, community []Card, numPlayers int) (EquityResult, error) {
+	return Enumerate(context.Background(), SimConfig{Hole: hole, Community: community, NumPlayers: numPlayers})
+}
+
+// Enumerate is the exact counterpart of Simulate; cfg.NumSims, Workers and
+// Seed are unused. See SimConfig.ExactDeals for the cost.
+func Enumerate(ctx context.Context, cfg SimConfig) (EquityResult, error) {
+	if err := cfg.validate(); err != nil {
+		return EquityResult{}, err
+	}
+	results, err := EnumerateEquity(ctx, cfg.equity())
+	if err != nil {
+		return EquityResult{}, err
+	}
+	results[0].OpponentRanks = poolRanks(results[1:])
+	return results[0], nil
+}
+
+// ExactDeals bounds the deals Enumerate walks for cfg.
+func (cfg SimConfig) ExactDeals() int64 {
+	if cfg.validate() != nil {
+		return math.MaxInt64
+	}
+	return ExactDeals(cfg.equity())
+}
+
+// ExactDeals returns an upper bound on the deals EnumerateEquity walks for
+// cfg: every live combo of each range player, every holding of each random
+// player and every completion of the board. It saturates at math.MaxInt64.
+func ExactDeals(cfg EquityConfig) int64 {
+	known := cfg.known()
+	unseen := NumCards - known.Count()
+	holeCards := cfg.Variant.HoleCards()
+	deals := int64(1)
+	for _, r := range cfg.Players {
+		if r == nil {
+			deals = satMul(deals, choose(unseen, holeCards))
+		} else {
+			deals = satMul(deals, int64(len(r.Without(known))))
+		}
+		unseen -= holeCards
+	}
+	return satMul(deals, choose(unseen, 5-len(cfg.Community)))
+}
+
+// EnumerateEquity walks every combination of the players' holdings and every
+// remaining board and returns each player's exact result. Range combos count
+// with their weights. ExactDeals bounds the cost; it stops early with
+// ctx.Err() when ctx is cancelled.
+func EnumerateEquity(ctx context.Context, cfg EquityConfig) ([]EquityResult, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	known := cfg.known()
+	e := enumerator{
+		ctx:     ctx,
+		variant: cfg.Variant,
+		players: make([]Range, len(cfg.Players)),
+		deck:    (fullDeck &^ known).Cards(),
+		board:   CardSetOf(cfg.Community),
+		needed:  5 - len(cfg.Community),
+		hands:   make([]CardSet, len(cfg.Players)),
+		values:  make([]HandValue, len(cfg.Players)),
+		tallies: make([]equityTally, len(cfg.Players)),
+	}
+	for i, r := range cfg.Players {
+		if r != nil {
+			e.players[i] = r.Without(known)
+		}
+	}
+	if cfg.Variant.HiLo() {
+		e.lows = make([]LowValue, len(cfg.Players))
+	}
+	e.rest = make([]Card, 0, len(e.deck))
+	e.deal(0, known, 1)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if e.tallies[0].deals == 0 {
+		return nil, &InvalidInputError{Msg: "player ranges leave no way to deal their hands together"}
+	}
+	results := make([]EquityResult, len(e.tallies))
+	for i, t := range e.tallies {
+		results[i] = t.result(true)
+	}
+	return results, nil
+}
+
+type enumerator struct {
+	ctx     context.Context
+	variant Variant
+	players []Range // live combos per player; nil deals every holding
+	deck    []Card  // every card not known
+	rest    []Card  // scratch: deck cards not yet dealt
+	board   CardSet
+	needed  int
+	hands   []CardSet
+	values  []HandValue
+	lows    []LowValue // hi-lo variants only
+	tallies []equityTally
+}
+
+// deal gives player p each of its possible hands in turn, then walks the
+// boards once every player holds cards.
+func (e *enumerator) deal(p int, used CardSet, weight float64) {
+	if e.ctx.Err() != nil {
+		return
+	}
+	if p == len(e.players) {
+		e.rest = e.rest[:0]
+		for _, c := range e.deck {
+			if !used.Contains(c) {
+				e.rest = append(e.rest, c)
+			}
+		}
+		eachSubset(e.rest, e.needed, e.board, func(runout CardSet) {
+			for i, hand := range e.hands {
+				e.values[i] = e.variant.value(hand, runout)
+				if e.lows != nil {
+					e.lows[i] = e.variant.low(hand, runout)
+				}
+			}
+			recordDeal(e.tallies, e.values, e.lows, weight)
+		})
+		return
+	}
+	if r := e.players[p]; r != nil {
+		for _, c := range r {
+			hand := CardSetOf(c.Cards)
+			if used&hand == 0 {
+				e.hands[p] = hand
+				e.deal(p+1, used|hand, weight*c.Weight)
+			}
+		}
+		return
+	}
+	live := make([]Card, 0, len(e.deck))
+	for _, c := range e.deck {
+		if !used.Contains(c) {
+			live = append(live, c)
+		}
+	}
+	eachSubset(live, e.variant.HoleCards(), 0, func(hand CardSet) {
+		e.hands[p] = hand
+		e.deal(p+1, used|hand, weight)
+	})
+}
+
+// eachSubset calls fn with base plus every k-card subset of cards.
+func eachSubset(cards []Card, k int, base CardSet, fn func(CardSet)) {
+	if k == 0 {
+		fn(base)
+		return
+	}
+	for i := 0; i <= len(cards)-k; i++ {
+		eachSubset(cards[i+1:], k-1, base|cardBit(cards[i]), fn)
+	}
+}
+
+func choose(n, k int) int64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	c := int64(1)
+	for i := 0; i < k; i++ {
+		c = c * int64(n-i) / int64(i+1)
+	}
+	return c
+}
+
+func satMul(a, b int64) int64 {
+	if a != 0 && b > math.MaxInt64/a {
+		return math.MaxInt64
+	}
+	return a * b
+}
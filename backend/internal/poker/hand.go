@@ -1,6 +1,7 @@
 package poker
 
 import (
+	"fmt"
 	"sort"
 )
 
@@ -49,168 +50,74 @@ func (r RankType) String() string {
 
 // EvaluatedHand holds the best 5-card hand and its rank.
 type EvaluatedHand struct {
-	BestHand []Card   `json:"best_hand"`
-	Rank     RankType `json:"rank"`
-	RankName string   `json:"rank_name"`
+	BestHand []Card    `json:"best_hand"`
+	Rank     RankType  `json:"rank"`
+	RankName string    `json:"rank_name"`
+	Value    HandValue `json:"value"`
+	Low      *LowHand  `json:"low,omitempty"` // hi-lo variants; nil when no low qualifies
 }
 
 // EvaluateBestHand returns the best 5-card hand from 2 hole + up to 5 community cards.
 func EvaluateBestHand(hole []Card, community []Card) (EvaluatedHand, error) {
-	if len(hole) != 2 {
-		return EvaluatedHand{}, &InvalidInputError{Msg: "need exactly 2 hole cards"}
-	}
-	if len(community) > 5 {
-		return EvaluatedHand{}, &InvalidInputError{Msg: "max 5 community cards"}
-	}
-	all := append(append([]Card{}, hole...), community...)
-	best := selectBestFive(all)
-	rank := rankHand(best)
-	return EvaluatedHand{
-		BestHand: best,
-		Rank:     rank,
-		RankName: rank.String(),
-	}, nil
-}
-
-func selectBestFive(cards []Card) []Card {
-	if len(cards) <= 5 {
-		return copyAndSort(cards)
-	}
-	// All C(7,5) or C(n,5) combinations
-	return bestCombination(cards, 5)
-}
-
-func copyAndSort(c []Card) []Card {
-	out := make([]Card, len(c))
-	copy(out, c)
-	sort.Slice(out, func(i, j int) bool { return out[i].Rank > out[j].Rank })
-	return out
-}
-
-func bestCombination(cards []Card, k int) []Card {
-	var best []Card
-	var bestScore int64 = -1
-	combine(cards, 0, k, nil, func(sel []Card) {
-		sorted := copyAndSort(sel)
-		score := handScore(sorted)
-		if score > bestScore {
-			bestScore = score
-			best = sorted
-		}
-	})
-	return best
-}
-
-func combine(cards []Card, start, k int, curr []Card, fn func([]Card)) {
-	if k == 0 {
-		out := make([]Card, len(curr))
-		copy(out, curr)
-		fn(out)
-		return
-	}
-	for i := start; i <= len(cards)-k; i++ {
-		next := make([]Card, len(curr)+1)
-		copy(next, curr)
-		next[len(curr)] = cards[i]
-		combine(cards, i+1, k-1, next, fn)
-	}
+	return Holdem.EvaluateBestHand(hole, community)
 }
 
-// handScore returns a comparable int64 for the 5-card hand (higher = better).
-func handScore(c []Card) int64 {
-	r := rankHand(c)
-	// Score = (rank_type << 40) | (card values...)
-	var score int64 = int64(r) << 40
-	for i, c := range c {
-		score |= int64(c.Rank) << (8 * (4 - i))
+// EvaluateBestHand returns the best 5-card hand hole and community make
+// under v's rules. Hold'em takes up to 5 community cards, Omaha 3 to 5.
+func (v Variant) EvaluateBestHand(hole []Card, community []Card) (EvaluatedHand, error) {
+	if !v.Valid() {
+		return EvaluatedHand{}, &InvalidInputError{Msg: "unknown variant"}
 	}
-	return score
-}
-
-func rankHand(c []Card) RankType {
-	if len(c) != 5 {
-		return HighCard
+	if len(hole) != v.HoleCards() {
+		return EvaluatedHand{}, &InvalidInputError{Msg: fmt.Sprintf("need exactly %d hole cards", v.HoleCards())}
 	}
-	byRank := make(map[int]int)
-	bySuit := make(map[byte]int)
-	for _, card := range c {
-		byRank[card.Rank]++
-		bySuit[card.Suit]++
-	}
-
-	flush := len(bySuit) == 1
-	straightVal := straightHigh(byRank)
-
-	if flush && straightVal == RankA {
-		return RoyalFlush
-	}
-	if flush && straightVal > 0 {
-		return StraightFlush
-	}
-	if four := hasCount(byRank, 4); four {
-		return FourOfAKind
-	}
-	if three, two := hasCount(byRank, 3), hasCount(byRank, 2); three && two {
-		return FullHouse
-	}
-	if flush {
-		return Flush
-	}
-	if straightVal > 0 {
-		return Straight
-	}
-	if hasCount(byRank, 3) {
-		return ThreeOfAKind
-	}
-	pairs := countPairs(byRank)
-	if pairs == 2 {
-		return TwoPair
+	if len(community) > 5 {
+		return EvaluatedHand{}, &InvalidInputError{Msg: "max 5 community cards"}
 	}
-	if pairs == 1 {
-		return OnePair
+	if v.omaha() && len(community) < 3 {
+		return EvaluatedHand{}, &InvalidInputError{Msg: "need at least 3 community cards"}
 	}
-	return HighCard
-}
-
-func hasCount(byRank map[int]int, n int) bool {
-	for _, c := range byRank {
-		if c == n {
-			return true
-		}
+	all := append(append(make([]Card, 0, len(hole)+len(community)), hole...), community...)
+	if err := v.checkDeck(all); err != nil {
+		return EvaluatedHand{}, err
 	}
-	return false
-}
-
-func countPairs(byRank map[int]int) int {
-	n := 0
-	for _, c := range byRank {
-		if c == 2 {
-			n++
+	var seen CardSet
+	for _, c := range all {
+		if seen.Contains(c) {
+			return EvaluatedHand{}, &DuplicateCardError{Card: c.String()}
 		}
+		seen.Add(c)
 	}
-	return n
-}
-
-// straightHigh returns the high card of a straight, or 0 if not a straight.
-func straightHigh(byRank map[int]int) int {
-	if len(byRank) != 5 {
-		return 0
-	}
-	allRanks := make([]int, 0, 5)
-	for r := range byRank {
-		allRanks = append(allRanks, r)
+	var best []Card
+	var value HandValue
+	switch {
+	case v.omaha():
+		var five CardSet
+		value, five = omahaBest(CardSetOf(hole), CardSetOf(community))
+		best = bestFive(five.Cards(), value)
+	case len(all) < 5:
+		best = copyAndSort(all)
+		value = handValue(best)
+	default:
+		value = v.value(CardSetOf(hole), CardSetOf(community))
+		best = bestFive(all, value)
 	}
-	sort.Slice(allRanks, func(i, j int) bool { return allRanks[i] > allRanks[j] })
-	// A-5 wheel
-	if allRanks[0] == RankA && allRanks[1] == Rank5 && allRanks[2] == Rank4 &&
-		allRanks[3] == Rank3 && allRanks[4] == Rank2 {
-		return Rank5
+	h := EvaluatedHand{
+		BestHand: best,
+		Rank:     value.Rank(),
+		RankName: value.Rank().String(),
+		Value:    value,
 	}
-	for i := 0; i < len(allRanks)-1; i++ {
-		if allRanks[i]-allRanks[i+1] != 1 {
-			return 0
-		}
+	if v.HiLo() {
+		low, five := omahaLow(CardSetOf(hole), CardSetOf(community))
+		h.Low = newLowHand(five.Cards(), low)
 	}
-	return allRanks[0]
+	return h, nil
 }
 
+func copyAndSort(c []Card) []Card {
+	out := make([]Card, len(c))
+	copy(out, c)
+	sort.Slice(out, func(i, j int) bool { return out[i].Rank > out[j].Rank })
+	return out
+}
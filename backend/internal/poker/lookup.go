@@ -0,0 +1,171 @@
+package poker
+
+import (
+	"math/bits"
+)
+
+// The lookup evaluator scores 5 to 7 cards with two table reads and no
+// allocations. A hand holding five or more cards of one suit can only be
+// beaten by a straight flush in that suit, so it is scored from that suit's
+// 13-bit rank mask. Otherwise suits are irrelevant and the hand is scored
+// from its rank multiset, which is perfect-hashed to a dense index.
+
+const numRanks = 13
+
+var (
+	// flushValues maps a suit's rank mask (bit i = rank i+2) to the best
+	// flush or straight flush it contains. Only masks with 5-7 bits are set.
+	flushValues [1 << numRanks]HandValue
+
+	// rankValues[n-5] maps the multiset index of n ranks to the best
+	// non-flush value of those n cards.
+	rankValues [3][]HandValue
+
+	// binomial[n][k] = C(n, k) for the multiset index.
+	binomial [numRanks + 7][8]int
+)
+
+func init() {
+	for n := range binomial {
+		binomial[n][0] = 1
+		for k := 1; k < len(binomial[n]) && n > 0; k++ {
+			binomial[n][k] = binomial[n-1][k-1] + binomial[n-1][k]
+		}
+	}
+
+	// Five-card entries are scored directly; six and seven cards take the
+	// best entry with one card removed, which is the best 5-card subset.
+	for mask := 0; mask < len(flushValues); mask++ {
+		switch n := bits.OnesCount16(uint16(mask)); {
+		case n == 5:
+			var five []Card
+			for i := 0; i < numRanks; i++ {
+				if mask&(1<<i) != 0 {
+					five = append(five, Card{Suit: SuitSpades, Rank: Rank2 + i})
+				}
+			}
+			flushValues[mask] = handValue(five)
+		case n == 6 || n == 7:
+			for m := mask; m != 0; m &= m - 1 {
+				if v := flushValues[mask&^(m&-m)]; v > flushValues[mask] {
+					flushValues[mask] = v
+				}
+			}
+		}
+	}
+
+	for n := 5; n <= 7; n++ {
+		rankValues[n-5] = make([]HandValue, binomial[numRanks+n-1][n])
+		var counts [numRanks]uint8
+		fillRankValues(&counts, 0, n, n)
+	}
+}
+
+// fillRankValues enumerates every multiset of n ranks with at most four of
+// each rank and records its best non-flush value.
+func fillRankValues(counts *[numRanks]uint8, rank, left, n int) {
+	if rank < numRanks {
+		for c := 0; c <= 4 && c <= left; c++ {
+			counts[rank] = uint8(c)
+			fillRankValues(counts, rank+1, left-c, n)
+		}
+		counts[rank] = 0
+		return
+	}
+	if left != 0 {
+		return
+	}
+	idx := multisetIndex(counts)
+	if n == 5 {
+		rankValues[0][idx] = handValue(rainbow(counts))
+		return
+	}
+	for r := range counts {
+		if counts[r] == 0 {
+			continue
+		}
+		counts[r]--
+		if v := rankValues[n-6][multisetIndex(counts)]; v > rankValues[n-5][idx] {
+			rankValues[n-5][idx] = v
+		}
+		counts[r]++
+	}
+}
+
+// rainbow deals five ranks out with suits cycling H, D, C, S so that they
+// can never form a flush.
+func rainbow(counts *[numRanks]uint8) []Card {
+	suits := [4]byte{SuitHearts, SuitDiamonds, SuitClubs, SuitSpades}
+	cards := make([]Card, 0, 5)
+	for i, c := range counts {
+		for j := 0; j < int(c); j++ {
+			cards = append(cards, Card{Suit: suits[len(cards)%4], Rank: Rank2 + i})
+		}
+	}
+	return cards
+}
+
+// multisetIndex is the colexicographic rank of the sorted rank sequence
+// r1 <= ... <= rn, mapped to the distinct sequence ri+i-1 (stars and bars).
+func multisetIndex(counts *[numRanks]uint8) int {
+	idx, i := 0, 1
+	for r, c := range counts {
+		for j := uint8(0); j < c; j++ {
+			idx += binomial[r+i-1][i]
+			i++
+		}
+	}
+	return idx
+}
+
+// evaluate scores 5 to 7 distinct cards without allocating.
+func evaluate(cards []Card) HandValue {
+	return evaluateSet(CardSetOf(cards))
+}
+
+// evaluateSet scores a set of 5 to 7 cards without allocating.
+func evaluateSet(s CardSet) HandValue {
+	var counts [numRanks]uint8
+	for i := 0; i < 4; i++ {
+		m := s.suitMask(i)
+		if bits.OnesCount16(m) >= 5 {
+			return flushValues[m]
+		}
+		for ; m != 0; m &= m - 1 {
+			counts[bits.TrailingZeros16(m)]++
+		}
+	}
+	return rankValues[s.Count()-5][multisetIndex(&counts)]
+}
+
+// bestFive picks the five cards that make value out of cards, ordered as in
+// the value: grouped ranks first, kickers last, the wheel Ace at the end.
+func bestFive(cards []Card, value HandValue) []Card {
+	var flushSuit byte
+	if r := value.Rank(); r == Flush || r == StraightFlush || r == RoyalFlush {
+		set := CardSetOf(cards)
+		for i, suit := range indexToSuit {
+			if bits.OnesCount16(set.suitMask(i)) >= 5 {
+				flushSuit = suit
+			}
+		}
+	}
+
+	best := make([]Card, 0, 5)
+	var used uint8
+	for i := 4; i >= 0; i-- {
+		rank := int(value>>(4*i)) & 0xF
+		if rank == 1 {
+			rank = RankA
+		}
+		for j, c := range cards {
+			if used&(1<<j) != 0 || c.Rank != rank || (flushSuit != 0 && c.Suit != flushSuit) {
+				continue
+			}
+			used |= 1 << j
+			best = append(best, c)
+			break
+		}
+	}
+	return best
+}
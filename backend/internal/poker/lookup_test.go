@@ -0,0 +1,128 @@
+package poker
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// combinatorialBest is the evaluator the lookup tables replaced: score every
+// 5-card subset and keep the best. Kept as a reference and benchmark baseline.
+func combinatorialBest(cards []Card) []Card {
+	var best []Card
+	var bestValue HandValue
+	combine(cards, 0, 5, nil, func(sel []Card) {
+		sorted := make([]Card, len(sel))
+		copy(sorted, sel)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Rank > sorted[j].Rank })
+		value := handValue(sorted)
+		if best == nil || value > bestValue {
+			bestValue = value
+			best = sorted
+		}
+	})
+	return best
+}
+
+func combine(cards []Card, start, k int, curr []Card, fn func([]Card)) {
+	if k == 0 {
+		out := make([]Card, len(curr))
+		copy(out, curr)
+		fn(out)
+		return
+	}
+	for i := start; i <= len(cards)-k; i++ {
+		next := make([]Card, len(curr)+1)
+		copy(next, curr)
+		next[len(curr)] = cards[i]
+		combine(cards, i+1, k-1, next, fn)
+	}
+}
+
+func randomHands(n, size int, seed int64) [][]Card {
+	rng := rand.New(rand.NewSource(seed))
+	deck := allCards()
+	hands := make([][]Card, n)
+	for i := range hands {
+		rng.Shuffle(len(deck), func(a, b int) { deck[a], deck[b] = deck[b], deck[a] })
+		hands[i] = append([]Card{}, deck[:size]...)
+	}
+	return hands
+}
+
+func TestEvaluate_MatchesCombinatorial(t *testing.T) {
+	for size := 5; size <= 7; size++ {
+		for _, hand := range randomHands(50000, size, int64(size)) {
+			want := handValue(combinatorialBest(hand))
+			got := evaluate(hand)
+			if got != want {
+				t.Fatalf("evaluate(%v) = %x (%v), want %x (%v)", hand, got, got.Rank(), want, want.Rank())
+			}
+			best := bestFive(hand, got)
+			if len(best) != 5 || handValue(best) != got {
+				t.Fatalf("bestFive(%v) = %v, does not make %v", hand, best, got.Rank())
+			}
+		}
+	}
+}
+
+func TestEvaluate_EveryFlushMask(t *testing.T) {
+	for mask, v := range flushValues {
+		if v == 0 {
+			continue
+		}
+		var cards []Card
+		for i := 0; i < numRanks; i++ {
+			if mask&(1<<i) != 0 {
+				cards = append(cards, Card{Suit: SuitClubs, Rank: Rank2 + i})
+			}
+		}
+		if want := handValue(combinatorialBest(cards)); v != want {
+			t.Fatalf("flushValues[%013b] = %x, want %x", mask, v, want)
+		}
+	}
+}
+
+func TestEvaluateBestHand_DuplicateCard(t *testing.T) {
+	hole, _ := ParseCards([]string{"HA", "HK"})
+	community, _ := ParseCards([]string{"HA", "HJ", "HT"})
+	if _, err := EvaluateBestHand(hole, community); err == nil {
+		t.Fatal("want error for a card in both hole and community")
+	}
+}
+
+func BenchmarkEvaluate7_Lookup(b *testing.B) {
+	hands := randomHands(1024, 7, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		evaluate(hands[i%len(hands)])
+	}
+}
+
+func BenchmarkEvaluate7_Combinatorial(b *testing.B) {
+	hands := randomHands(1024, 7, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		handValue(combinatorialBest(hands[i%len(hands)]))
+	}
+}
+
+func BenchmarkEvaluateBestHand(b *testing.B) {
+	hands := randomHands(1024, 7, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		h := hands[i%len(hands)]
+		EvaluateBestHand(h[:2], h[2:])
+	}
+}
+
+func BenchmarkWinProbability(b *testing.B) {
+	hole, _ := ParseCards([]string{"HA", "HK"})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		WinProbability(hole, nil, 2, 1000)
+	}
+}
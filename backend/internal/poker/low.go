@@ -0,0 +1,162 @@
+package poker
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// LowValue scores an ace-to-five low that qualifies eight-or-better: bit r-1
+// is set for each of its five distinct ranks, the ace counting as 1.
+// Straights and flushes do not count against a low. Comparing values as
+// integers compares the hands from the highest card down, so a smaller
+// value is a better low; 0 means no low qualifies.
+type LowValue uint8
+
+// Compare returns 1 if v is the better low, -1 if o is, 0 if they tie.
+// Any qualifying low beats no low.
+func (v LowValue) Compare(o LowValue) int {
+	switch {
+	case v == o:
+		return 0
+	case o == 0 || (v != 0 && v < o):
+		return 1
+	}
+	return -1
+}
+
+// Ranks returns the ranks of the low, highest first, the ace as RankA.
+func (v LowValue) Ranks() []int {
+	ranks := make([]int, 0, 5)
+	for m := uint8(v); m != 0; m &^= 1 << (7 - bits.LeadingZeros8(m)) {
+		r := 8 - bits.LeadingZeros8(m)
+		if r == 1 {
+			r = RankA
+		}
+		ranks = append(ranks, r)
+	}
+	return ranks
+}
+
+// String writes the low as e.g. "8-6-4-2-A", or "" when none qualifies.
+func (v LowValue) String() string {
+	parts := make([]string, 0, 5)
+	for _, r := range v.Ranks() {
+		parts = append(parts, string(rankToChar[r]))
+	}
+	return strings.Join(parts, "-")
+}
+
+// LowHand is the best qualifying low a player holds.
+type LowHand struct {
+	Cards []Card   `json:"cards"` // highest first
+	Value LowValue `json:"value"`
+	Name  string   `json:"name"` // e.g. "8-6-4-2-A"
+}
+
+// EvaluateLow returns the best eight-or-better low from any five of cards,
+// as in Stud-8, or nil when none qualifies.
+func EvaluateLow(cards []Card) (*LowHand, error) {
+	set := CardSetOf(cards)
+	if set.Count() != len(cards) {
+		return nil, &InvalidInputError{Msg: "duplicate card"}
+	}
+	return newLowHand(cards, bestLow(lowRanks(set))), nil
+}
+
+func newLowHand(cards []Card, v LowValue) *LowHand {
+	if v == 0 {
+		return nil
+	}
+	h := &LowHand{Value: v, Name: v.String()}
+	for _, r := range v.Ranks() {
+		for _, c := range cards {
+			if c.Rank == r {
+				h.Cards = append(h.Cards, c)
+				break
+			}
+		}
+	}
+	return h
+}
+
+// lowRanks returns the ranks of eight or lower in s as a LowValue mask.
+func lowRanks(s CardSet) uint8 {
+	var m uint8
+	for i := 0; i < 4; i++ {
+		suit := s.suitMask(i)
+		m |= uint8(suit&0x7F)<<1 | uint8(suit>>(RankA-2))
+	}
+	return m
+}
+
+// bestLow keeps the five lowest of the ranks in m, or returns 0 when there
+// are fewer than five.
+func bestLow(m uint8) LowValue {
+	if bits.OnesCount8(m) < 5 {
+		return 0
+	}
+	for bits.OnesCount8(m) > 5 {
+		m &^= 1 << (7 - bits.LeadingZeros8(m))
+	}
+	return LowValue(m)
+}
+
+// omahaLow returns the best low made of exactly two hole cards and three
+// board cards, and the five cards that make it.
+func omahaLow(hole, board CardSet) (LowValue, CardSet) {
+	var hs, bs [5]CardSet
+	nh, nb := splitCards(hole, &hs), splitCards(board, &bs)
+	var best LowValue
+	var bestSet CardSet
+	for i := 0; i < nh; i++ {
+		for j := i + 1; j < nh; j++ {
+			two := lowRanks(hs[i] | hs[j])
+			if bits.OnesCount8(two) != 2 {
+				continue
+			}
+			for a := 0; a < nb; a++ {
+				for b := a + 1; b < nb; b++ {
+					for c := b + 1; c < nb; c++ {
+						three := lowRanks(bs[a] | bs[b] | bs[c])
+						if bits.OnesCount8(three) != 3 || two&three != 0 {
+							continue
+						}
+						if v := LowValue(two | three); v.Compare(best) > 0 {
+							best, bestSet = v, hs[i]|hs[j]|bs[a]|bs[b]|bs[c]
+						}
+					}
+				}
+			}
+		}
+	}
+	return best, bestSet
+}
+
+// HiLoResult says who wins each half of a hi-lo pot. Without a qualifying
+// low the high hands take the whole pot.
+type HiLoResult struct {
+	High []int `json:"high"` // players tied for the best high hand
+	Low  []int `json:"low"`  // players tied for the best qualifying low; empty if none
+}
+
+// Scoop reports whether one player wins the whole pot.
+func (r HiLoResult) Scoop() bool {
+	return len(r.High) == 1 && (len(r.Low) == 0 || len(r.Low) == 1 && r.Low[0] == r.High[0])
+}
+
+// Shares returns each of players' fraction of the pot: each half is divided
+// evenly between its winners.
+func (r HiLoResult) Shares(players int) []float64 {
+	shares := make([]float64, players)
+	high := 1.0
+	if len(r.Low) > 0 {
+		high = 0.5
+		for _, p := range r.Low {
+			shares[p] += 0.5 / float64(len(r.Low))
+		}
+	}
+	for _, p := range r.High {
+		shares[p] += high / float64(len(r.High))
+	}
+	return shares
+}
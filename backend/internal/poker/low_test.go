@@ -0,0 +1,129 @@
+package poker
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateLow(t *testing.T) {
+	tests := []struct {
+		cards []string
+		want  string // "" for no qualifying low
+	}{
+		{[]string{"HA", "D2", "C3", "S9", "HK", "D8", "C7"}, "8-7-3-2-A"},
+		{[]string{"HA", "D2", "C3", "S4", "H5", "D6", "C7"}, "5-4-3-2-A"}, // the straight still counts as a low
+		{[]string{"HA", "DA", "C2", "S2", "H3", "D8", "C7"}, "8-7-3-2-A"}, // pairs are skipped
+		{[]string{"HA", "D2", "C3", "S9", "HK", "D8", "C3"}, ""},          // four low ranks
+		{[]string{"H9", "D2", "C3", "S4", "H5"}, ""},                      // nine is not a low card
+	}
+	for _, tt := range tests {
+		cards, err := ParseCards(tt.cards)
+		var low *LowHand
+		if err == nil {
+			low, err = EvaluateLow(cards)
+		}
+		switch {
+		case tt.want == "" && low != nil:
+			t.Errorf("%v: got low %s, want none", tt.cards, low.Name)
+		case tt.want != "" && (low == nil || low.Name != tt.want || len(low.Cards) != 5):
+			t.Errorf("%v: got %+v (err %v), want %s", tt.cards, low, err, tt.want)
+		}
+	}
+}
+
+func TestLowValue_Compare(t *testing.T) {
+	wheel := bestLow(0b00011111)
+	eightSix := LowValue(0b10101011) // 8-6-4-2-A
+	eightSeven := LowValue(0b11000111)
+	if wheel.Compare(eightSix) != 1 || eightSeven.Compare(eightSix) != -1 || eightSix.Compare(0) != 1 || LowValue(0).Compare(0) != 0 {
+		t.Error("low ordering broken")
+	}
+	if eightSix.String() != "8-6-4-2-A" {
+		t.Errorf("String = %q", eightSix.String())
+	}
+}
+
+func TestOmaha8_Showdown(t *testing.T) {
+	board, _ := ParseCards([]string{"H3", "C5", "D7", "SK", "SQ"})
+	hands := [][]string{
+		{"SA", "ST", "HK", "DK"}, // set of kings, no low
+		{"HA", "D2", "C9", "D9"}, // 7-5-3-2-A low
+		{"DA", "C2", "H9", "S9"}, // same low
+	}
+	players := make([][]Card, len(hands))
+	for i, h := range hands {
+		players[i], _ = ParseCards(h)
+	}
+	res, err := Omaha8.Showdown(board, players)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.Winners, []int{0}) || !reflect.DeepEqual(res.LowWinners, []int{1, 2}) {
+		t.Errorf("high %v low %v, want [0] and [1 2]", res.Winners, res.LowWinners)
+	}
+	if res.Hands[0].Low != nil || res.Hands[1].Low == nil || res.Hands[1].Low.Name != "7-5-3-2-A" {
+		t.Errorf("lows: %+v %+v", res.Hands[0].Low, res.Hands[1].Low)
+	}
+	if !res.Split() || !reflect.DeepEqual(res.Shares(), []float64{0.5, 0.25, 0.25}) {
+		t.Errorf("split %v shares %v, want a quartered low", res.Split(), res.Shares())
+	}
+
+	// Without a low the high hand scoops.
+	board[0] = Card{Suit: SuitHearts, Rank: RankJ}
+	if res, err = Omaha8.Showdown(board, players); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.LowWinners) != 0 || res.Split() || res.Shares()[0] != 1 {
+		t.Errorf("no low: %+v, want player 0 to scoop", res)
+	}
+
+	// The same hands in PLO4 never consider a low.
+	if res, _ = Omaha4.Showdown(board, players); res.Hands[1].Low != nil || res.LowWinners != nil {
+		t.Errorf("PLO4 reported a low: %+v", res)
+	}
+}
+
+func TestOmaha8_LowUsesTwoHoleCards(t *testing.T) {
+	// Only one low card in hand: the board's four low cards do not help.
+	hole, _ := ParseCards([]string{"HA", "SK", "DK", "CQ"})
+	board, _ := ParseCards([]string{"D2", "C3", "H4", "S5", "HJ"})
+	h, err := Omaha8.EvaluateBestHand(hole, board)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Low != nil {
+		t.Errorf("got low %s from one hole card", h.Low.Name)
+	}
+}
+
+func TestOmaha8_Equity(t *testing.T) {
+	hi, _ := ParseCards([]string{"SA", "SK", "HK", "DQ"})
+	lo, _ := ParseCards([]string{"HA", "D2", "C4", "S9"})
+	community, _ := ParseCards([]string{"H3", "C7", "DK", "SJ"})
+	cfg := EquityConfig{
+		Variant:   Omaha8,
+		Players:   []Range{HandRange(hi...), HandRange(lo...)},
+		Community: community,
+		NumSims:   50000,
+		Seed:      8,
+	}
+	exact, err := EnumerateEquity(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum := exact[0].Equity + exact[1].Equity; math.Abs(sum-1) > 1e-12 {
+		t.Errorf("equities sum to %v, want 1", sum)
+	}
+	if exact[1].Tie == 0 || exact[0].Win == 0 {
+		t.Errorf("want both scoops and split pots: %+v", exact)
+	}
+	mc, err := SimulateEquity(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(mc[0].Equity-exact[0].Equity) > 4*mc[0].EquityStdErr {
+		t.Errorf("Monte Carlo %+v too far from exact %+v", mc[0], exact[0])
+	}
+}
@@ -1,107 +1,319 @@
 package poker
 
 import (
+	"context"
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
 	"math/rand"
-	"time"
+	"runtime"
+	"sync"
+	"sync/atomic"
 )
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
+// simChunk is the number of simulations drawn from one RNG stream. Chunks,
+// not workers, own the random streams, so the result for a seed does not
+// depend on how many workers share the chunks.
+const simChunk = 4096
+
+// MaxSims is the most simulations one request may ask for.
+const MaxSims = 1000000
 
-// Full deck of 52 cards as strings
-var fullDeck []string
+// SimConfig describes an equity calculation from our seat.
+type SimConfig struct {
+	Variant    Variant // Hold'em unless set
+	Hole       []Card  // the variant's number of hole cards
+	Community  []Card  // 0-5 known community cards
+	Dead       []Card  // cards out of play, e.g. burned or exposed
+	NumPlayers int     // 2-10, including us
+	NumSims    int     // 1-MaxSims
+	Ranges     []Range // per opponent in seat order; missing or nil ranges deal random cards
+	Workers    int     // goroutines to shard across; 0 means GOMAXPROCS
+	Seed       int64   // 0 picks a random seed; the result reports the seed used
+}
 
-func init() {
-	suits := []byte{'H', 'D', 'C', 'S'}
-	ranks := []byte{'A', 'K', 'Q', 'J', 'T', '9', '8', '7', '6', '5', '4', '3', '2'}
-	for _, s := range suits {
-		for _, r := range ranks {
-			fullDeck = append(fullDeck, string([]byte{s, r}))
+func (cfg SimConfig) validate() error {
+	if !cfg.Variant.Valid() {
+		return &InvalidInputError{Msg: "unknown variant"}
+	}
+	if len(cfg.Hole) != cfg.Variant.HoleCards() {
+		return &InvalidInputError{Msg: fmt.Sprintf("need exactly %d hole cards", cfg.Variant.HoleCards())}
+	}
+	if len(cfg.Community) > 5 {
+		return &InvalidInputError{Msg: "max 5 community cards"}
+	}
+	if cfg.NumPlayers < 2 || cfg.NumPlayers > 10 {
+		return &InvalidInputError{Msg: "num_players must be 2-10"}
+	}
+	if cfg.Workers < 0 {
+		return &InvalidInputError{Msg: "workers must not be negative"}
+	}
+	var known CardSet
+	for _, cards := range [][]Card{cfg.Hole, cfg.Community, cfg.Dead} {
+		for _, c := range cards {
+			if known.Contains(c) {
+				return &DuplicateCardError{Card: c.String()}
+			}
+			known.Add(c)
+		}
+	}
+	if len(cfg.Ranges) > cfg.NumPlayers-1 {
+		return &InvalidInputError{Msg: "more ranges than opponents"}
+	}
+	for i, r := range cfg.Ranges {
+		if r != nil && len(r.Without(known)) == 0 {
+			return &InvalidInputError{Msg: fmt.Sprintf("opponent %d: no combo in range is still live", i+1)}
 		}
 	}
+	return nil
 }
 
-// WinProbability runs Monte Carlo simulation and returns win probability for the given hand.
-// hole: 2 hole cards, community: 0-5 known community cards, numPlayers: 2-10, numSims: simulations to run.
-func WinProbability(hole []Card, community []Card, numPlayers, numSims int) (float64, error) {
-	if len(hole) != 2 {
-		return 0, &InvalidInputError{Msg: "need exactly 2 hole cards"}
+// equity puts us in seat 0 of an EquityConfig.
+func (cfg SimConfig) equity() EquityConfig {
+	players := make([]Range, cfg.NumPlayers)
+	players[0] = HandRange(cfg.Hole...)
+	copy(players[1:], cfg.Ranges)
+	return EquityConfig{
+		Variant:   cfg.Variant,
+		Players:   players,
+		Community: cfg.Community,
+		Dead:      cfg.Dead,
+		NumSims:   cfg.NumSims,
+		Workers:   cfg.Workers,
+		Seed:      cfg.Seed,
 	}
-	if len(community) > 5 {
-		return 0, &InvalidInputError{Msg: "max 5 community cards"}
+}
+
+// WinProbability runs Monte Carlo simulation and returns win/tie/loss rates and pot equity for the given hand.
+// hole: 2 hole cards, community: 0-5 known community cards, numPlayers: 2-10, numSims: simulations to run.
+func WinProbability(hole []Card, community []Card, numPlayers, numSims int) (EquityResult, error) {
+	return Simulate(context.Background(), SimConfig{
+		Hole:       hole,
+		Community:  community,
+		NumPlayers: numPlayers,
+		NumSims:    numSims,
+	})
+}
+
+// maxSeed is the largest seed NewSeed returns: 2^53-1, so seeds survive
+// JSON clients that read numbers as doubles.
+const maxSeed = 1<<53 - 1
+
+// NewSeed returns a random simulation seed in [1, 2^53-1].
+func NewSeed() int64 {
+	var b [8]byte
+	for {
+		if _, err := crand.Read(b[:]); err != nil {
+			panic("poker: reading random seed: " + err.Error())
+		}
+		if seed := int64(binary.LittleEndian.Uint64(b[:]) & maxSeed); seed != 0 {
+			return seed
+		}
 	}
-	if numPlayers < 2 || numPlayers > 10 {
-		return 0, &InvalidInputError{Msg: "num_players must be 2-10"}
+}
+
+// Simulate runs the simulation from our seat: we hold cfg.Hole against
+// cfg.NumPlayers-1 opponents. See SimulateEquity.
+func Simulate(ctx context.Context, cfg SimConfig) (EquityResult, error) {
+	if err := cfg.validate(); err != nil {
+		return EquityResult{}, err
 	}
-	if numSims < 1 || numSims > 1000000 {
-		return 0, &InvalidInputError{Msg: "num_sims must be 1-1000000"}
+	results, err := SimulateEquity(ctx, cfg.equity())
+	if err != nil {
+		return EquityResult{}, err
 	}
+	results[0].OpponentRanks = poolRanks(results[1:])
+	return results[0], nil
+}
 
-	used := make(map[string]bool)
-	for _, c := range hole {
-		used[c.String()] = true
+// maxRejects bounds how often in a row range players may be dealt
+// overlapping combos before the simulation gives up.
+const maxRejects = 1000
+
+// SimulateEquity deals cfg.NumSims random runouts on a pool of workers, each
+// with a private RNG, and returns every player's result. It stops early with
+// ctx.Err() when ctx is cancelled. The same config and Seed always give the
+// same results, whatever the number of workers.
+func SimulateEquity(ctx context.Context, cfg EquityConfig) ([]EquityResult, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.NumSims < 1 || cfg.NumSims > MaxSims {
+		return nil, &InvalidInputError{Msg: fmt.Sprintf("num_sims must be 1-%d", MaxSims)}
 	}
-	for _, c := range community {
-		used[c.String()] = true
+	if cfg.Seed == 0 {
+		cfg.Seed = NewSeed()
+	}
+	workers := cfg.Workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunks := (cfg.NumSims + simChunk - 1) / simChunk
+	if workers > chunks {
+		workers = chunks
 	}
 
-	wins := 0
-	for i := 0; i < numSims; i++ {
-		deck := make([]string, 0, 52)
-		for _, s := range fullDeck {
-			if !used[s] {
-				deck = append(deck, s)
+	tallies := make([][]equityTally, chunks)
+	errs := make([]error, chunks)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := newSimulator(cfg)
+			for ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= chunks {
+					return
+				}
+				n := simChunk
+				if i == chunks-1 {
+					n = cfg.NumSims - i*simChunk
+				}
+				tallies[i], errs[i] = s.run(chunkSeed(cfg.Seed, i), n)
 			}
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	total := make([]equityTally, len(cfg.Players))
+	for _, chunk := range tallies {
+		for p := range total {
+			total[p].add(chunk[p])
 		}
-		shuffle(deck)
-
-		// Deal remaining community cards
-		needed := 5 - len(community)
-		commCards := append([]Card{}, community...)
-		for j := 0; j < needed; j++ {
-			c, _ := ParseCard(deck[j])
-			commCards = append(commCards, c)
+	}
+	results := make([]EquityResult, len(total))
+	for p, t := range total {
+		results[p] = t.result(false)
+		results[p].Seed = cfg.Seed
+	}
+	return results, nil
+}
+
+// chunkSeed derives the seed of chunk i with a splitmix64 step.
+func chunkSeed(seed int64, i int) int64 {
+	z := uint64(seed) + uint64(i+1)*0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	return int64(z ^ z>>31)
+}
+
+// simulator holds one worker's deck and RNG.
+type simulator struct {
+	variant   Variant
+	holeCards int
+	known     CardSet // community and dead cards
+	board     CardSet
+	base      []Card // the deck each run starts from
+	deck      *Deck
+	needed    int             // community cards still to come
+	ranges    []*rangeSampler // per player; nil deals random cards
+	random    int             // players dealt random cards
+	hands     []CardSet
+	values    []HandValue
+	lows      []LowValue // hi-lo variants only
+	src       rand.Source
+	rng       *rand.Rand
+}
+
+func newSimulator(cfg EquityConfig) *simulator {
+	known := cfg.known()
+	base := (fullDeck &^ known).Cards()
+	s := &simulator{
+		variant:   cfg.Variant,
+		holeCards: cfg.Variant.HoleCards(),
+		known:     known,
+		board:     CardSetOf(cfg.Community),
+		base:      base,
+		needed:    5 - len(cfg.Community),
+		ranges:    make([]*rangeSampler, len(cfg.Players)),
+		hands:     make([]CardSet, len(cfg.Players)),
+		values:    make([]HandValue, len(cfg.Players)),
+		src:       rand.NewSource(0),
+	}
+	s.rng = rand.New(s.src)
+	if cfg.Variant.HiLo() {
+		s.lows = make([]LowValue, len(cfg.Players))
+	}
+	for i, r := range cfg.Players {
+		if r == nil {
+			s.random++
+		} else {
+			s.ranges[i] = newRangeSampler(r.Without(known))
 		}
-		// Opponents get (numPlayers-1)*2 hole cards from deck[needed:]
-		oppStart := needed
-		oppCards := (numPlayers - 1) * 2
-		if oppStart+oppCards > len(deck) {
-			continue
+	}
+	return s
+}
+
+// run plays n deals from a fresh deck and RNG stream.
+func (s *simulator) run(seed int64, n int) ([]equityTally, error) {
+	s.src.Seed(seed)
+	s.deck = DeckOf(s.base)
+
+	tallies := make([]equityTally, len(s.hands))
+	for i := 0; i < n; i++ {
+		used, err := s.dealRanges()
+		if err != nil {
+			return nil, err
 		}
 
-		// Evaluate our hand
-		ourHand, _ := EvaluateBestHand(hole, commCards)
-		ourScore := handScore(ourHand.BestHand)
-
-		// Evaluate each opponent's hand
-		weWin := true
-		for k := 0; k < numPlayers-1; k++ {
-			oh1, _ := ParseCard(deck[oppStart+k*2])
-			oh2, _ := ParseCard(deck[oppStart+k*2+1])
-			oppHand, _ := EvaluateBestHand([]Card{oh1, oh2}, commCards)
-			oppScore := handScore(oppHand.BestHand)
-			if oppScore > ourScore {
-				weWin = false
-				break
+		// Deal remaining community cards, then random hands
+		s.deck.Reset()
+		cards, err := s.deck.dealRandom(s.rng, s.needed+s.holeCards*s.random, used)
+		if err != nil {
+			return nil, err
+		}
+		runout := s.board
+		for _, c := range cards[:s.needed] {
+			runout.Add(c)
+		}
+		cards = cards[s.needed:]
+		for p, r := range s.ranges {
+			if r == nil {
+				s.hands[p] = CardSetOf(cards[:s.holeCards])
+				cards = cards[s.holeCards:]
 			}
-			if oppScore == ourScore {
-				// Tie: we split, count as half win for simplicity
-				// Actually for "win probability" we typically mean strictly win. Let's not count ties as wins.
-				weWin = false
-				break
+			s.values[p] = s.variant.value(s.hands[p], runout)
+			if s.lows != nil {
+				s.lows[p] = s.variant.low(s.hands[p], runout)
 			}
 		}
-		if weWin {
-			wins++
-		}
+		recordDeal(tallies, s.values, s.lows, 1)
 	}
-	return float64(wins) / float64(numSims), nil
+	return tallies, nil
 }
 
-func shuffle(a []string) {
-	for i := len(a) - 1; i > 0; i-- {
-		j := rand.Intn(i + 1)
-		a[i], a[j] = a[j], a[i]
+// dealRanges draws a combo for every range player and returns the cards in
+// use. A deal where combos collide is redrawn, which leaves every
+// non-colliding deal weighted by the product of its combo weights.
+func (s *simulator) dealRanges() (CardSet, error) {
+	for rejects := 0; rejects < maxRejects; rejects++ {
+		used, ok := s.known, true
+		for p, r := range s.ranges {
+			if r == nil {
+				continue
+			}
+			c := r.sample(s.rng.Float64())
+			hand := CardSetOf(c.Cards)
+			if used&hand != 0 {
+				ok = false
+				break
+			}
+			s.hands[p] = hand
+			used |= hand
+		}
+		if ok {
+			return used, nil
+		}
 	}
+	return 0, &InvalidInputError{Msg: "player ranges leave no way to deal their hands together"}
 }
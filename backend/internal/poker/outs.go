@@ -0,0 +1,137 @@
+package poker
+
+import "sort"
+
+// OutsConfig is a hand drawing on the flop or the turn.
+type OutsConfig struct {
+	Variant   Variant
+	Hole      []Card
+	Community []Card // the flop, or the flop and the turn
+	Opponent  []Card // optional; when set, only cards that win against it count
+}
+
+// OutGroup is the outs that make one hand category.
+type OutGroup struct {
+	Rank     RankType `json:"rank"`
+	RankName string   `json:"rank_name"`
+	Cards    []Card   `json:"cards"`
+}
+
+// OutsResult lists the unseen cards that improve a hand. Without an
+// opponent an out lifts the hand to a better category than it holds now and
+// than the board makes on its own; against an opponent it turns a losing
+// hand into a winning one. Only high hands count, also in hi-lo.
+type OutsResult struct {
+	Hand     EvaluatedHand  `json:"hand"` // what the hand makes now
+	Opponent *EvaluatedHand `json:"opponent,omitempty"`
+	Behind   bool           `json:"behind"` // the opponent's hand is better now
+	Outs     []OutGroup     `json:"outs"`   // best category first
+	Count    int            `json:"count"`
+	Unseen   int            `json:"unseen"` // cards the next one may be
+	// NextCard and ByRiver are the chances the hand is improved, or against
+	// an opponent winning, after the next card and after the river. ByRiver
+	// counts every runout, so runner-runner draws count; a hand ahead of the
+	// opponent has no outs but scores the runouts it stays ahead on.
+	NextCard float64 `json:"next_card"`
+	ByRiver  float64 `json:"by_river"`
+}
+
+// Outs finds the cards that improve a hand on the flop or the turn.
+func Outs(cfg OutsConfig) (OutsResult, error) {
+	v := cfg.Variant
+	if n := len(cfg.Community); n != 3 && n != 4 {
+		return OutsResult{}, &InvalidInputError{Msg: "need 3 or 4 community cards: the flop, or the flop and the turn"}
+	}
+	hand, err := v.EvaluateBestHand(cfg.Hole, cfg.Community)
+	if err != nil {
+		return OutsResult{}, err
+	}
+	res := OutsResult{Hand: hand, Outs: []OutGroup{}}
+	known := CardSetOf(cfg.Hole) | CardSetOf(cfg.Community)
+	if cfg.Opponent != nil {
+		opp, err := v.EvaluateBestHand(cfg.Opponent, cfg.Community)
+		if err != nil {
+			return OutsResult{}, &InvalidInputError{Msg: "opponent: " + err.Error()}
+		}
+		if known&CardSetOf(cfg.Opponent) != 0 {
+			return OutsResult{}, &InvalidInputError{Msg: "opponent holds a card already in play"}
+		}
+		known |= CardSetOf(cfg.Opponent)
+		res.Opponent = &opp
+		res.Behind = hand.Value < opp.Value
+	}
+
+	// improved reports whether the hand is better off on board than now. The
+	// cards were all checked above.
+	improved := func(board []Card) (bool, EvaluatedHand) {
+		h, _ := v.EvaluateBestHand(cfg.Hole, board)
+		if cfg.Opponent != nil {
+			opp, _ := v.EvaluateBestHand(cfg.Opponent, board)
+			return h.Value > opp.Value, h
+		}
+		return v.outranks(h.Rank, hand.Rank) && (v.omaha() || v.outranks(h.Rank, v.boardRank(board))), h
+	}
+
+	unseen := (v.Deck() &^ known).Cards()
+	res.Unseen = len(unseen)
+	board := make([]Card, 5)
+	copy(board, cfg.Community)
+	next := len(cfg.Community)
+	drawing := cfg.Opponent == nil || res.Behind
+	groups := make(map[RankType][]Card)
+	hits := 0
+	for _, c := range unseen {
+		board[next] = c
+		if ok, h := improved(board[:next+1]); ok {
+			hits++
+			if drawing {
+				groups[h.Rank] = append(groups[h.Rank], c)
+				res.Count++
+			}
+		}
+	}
+	for rank, cards := range groups {
+		res.Outs = append(res.Outs, OutGroup{Rank: rank, RankName: rank.String(), Cards: cards})
+	}
+	sort.Slice(res.Outs, func(i, j int) bool { return v.outranks(res.Outs[i].Rank, res.Outs[j].Rank) })
+	res.NextCard = float64(hits) / float64(len(unseen))
+
+	if next == 4 { // the next card is the river
+		res.ByRiver = res.NextCard
+		return res, nil
+	}
+	hits = 0
+	runouts := 0
+	for i, turn := range unseen {
+		board[3] = turn
+		for _, river := range unseen[i+1:] {
+			board[4] = river
+			if ok, _ := improved(board); ok {
+				hits++
+			}
+			runouts++
+		}
+	}
+	res.ByRiver = float64(hits) / float64(runouts)
+	return res, nil
+}
+
+// outranks reports whether category a beats category b under v's rules.
+func (v Variant) outranks(a, b RankType) bool {
+	order := func(r RankType) int {
+		if v == ShortDeck && r == Flush {
+			return 2*int(FullHouse) + 1
+		}
+		return 2 * int(r)
+	}
+	return order(a) > order(b)
+}
+
+// boardRank is the category the board makes on its own, which every player
+// holds in hold'em.
+func (v Variant) boardRank(board []Card) RankType {
+	if len(board) < 5 {
+		return handValue(board).Rank()
+	}
+	return v.value(0, CardSetOf(board)).Rank()
+}
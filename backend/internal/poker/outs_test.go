@@ -0,0 +1,82 @@
+package poker
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOuts_FlushDrawAndOvercards(t *testing.T) {
+	hole, _ := ParseCards([]string{"HA", "HK"})
+	flop, _ := ParseCards([]string{"H7", "H2", "C9"})
+	res, err := Outs(OutsConfig{Hole: hole, Community: flop})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Count != 15 || len(res.Outs) != 2 || res.Outs[0].Rank != Flush || len(res.Outs[0].Cards) != 9 || res.Outs[1].Rank != OnePair {
+		t.Fatalf("outs = %+v", res.Outs)
+	}
+	if res.Unseen != 47 || math.Abs(res.NextCard-15.0/47) > 1e-12 {
+		t.Errorf("next card: %d unseen, %v", res.Unseen, res.NextCard)
+	}
+	// Missing twice takes one of the 32 blanks on both streets.
+	if want := 1 - 32.0*31/(47*46); math.Abs(res.ByRiver-want) > 1e-12 {
+		t.Errorf("by river = %v, want %v", res.ByRiver, want)
+	}
+}
+
+func TestOuts_BoardPairDoesNotCount(t *testing.T) {
+	hole, _ := ParseCards([]string{"HA", "SK"})
+	turn, _ := ParseCards([]string{"D7", "C2", "H9", "S4"})
+	res, err := Outs(OutsConfig{Hole: hole, Community: turn})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only aces and kings pair the hand; a 7 pairs the board for everyone.
+	if res.Count != 6 || res.ByRiver != res.NextCard || res.NextCard != 6.0/46 {
+		t.Errorf("outs = %+v, by river %v", res.Outs, res.ByRiver)
+	}
+}
+
+func TestOuts_AgainstOpponent(t *testing.T) {
+	hole, _ := ParseCards([]string{"HA", "HK"})
+	sets, _ := ParseCards([]string{"S9", "D9"})
+	flop, _ := ParseCards([]string{"H7", "H2", "C9"})
+	res, err := Outs(OutsConfig{Hole: hole, Community: flop, Opponent: sets})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The nine of hearts makes a flush but gives the set quads.
+	if !res.Behind || res.Count != 8 || res.Outs[0].Rank != Flush || res.Unseen != 45 {
+		t.Errorf("behind %v, outs %+v", res.Behind, res.Outs)
+	}
+	if res.ByRiver <= 0.2 || res.ByRiver >= 8.0/45*2 {
+		t.Errorf("by river = %v", res.ByRiver)
+	}
+
+	ahead, err := Outs(OutsConfig{Hole: sets, Community: flop, Opponent: hole})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ahead.Behind || ahead.Count != 0 || math.Abs(ahead.ByRiver+res.ByRiver-1) > 1e-12 || ahead.NextCard != 37.0/45 {
+		t.Errorf("ahead: %+v", ahead)
+	}
+	// On the turn the set loses only to the seven hearts that don't pair
+	// the board.
+	turn := append(flop[:3:3], Card{})
+	turn[3], _ = ParseCard("S4")
+	ahead, err = Outs(OutsConfig{Hole: sets, Community: turn, Opponent: hole})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ahead.Count != 0 || ahead.Unseen != 44 || ahead.NextCard != 37.0/44 || ahead.ByRiver != ahead.NextCard {
+		t.Errorf("ahead on the turn: %+v", ahead)
+	}
+
+	if _, err := Outs(OutsConfig{Hole: hole, Community: flop, Opponent: hole}); !IsInvalidInput(err) {
+		t.Errorf("shared cards: err = %v", err)
+	}
+	river, _ := ParseCards([]string{"H7", "H2", "C9", "S4", "D5"})
+	if _, err := Outs(OutsConfig{Hole: hole, Community: river}); !IsInvalidInput(err) {
+		t.Errorf("river: err = %v", err)
+	}
+}
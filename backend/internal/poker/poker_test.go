@@ -1,6 +1,10 @@
 package poker
 
 import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
 	"testing"
 )
 
@@ -77,3 +81,212 @@ func TestCompareHands(t *testing.T) {
 	_ = h1
 	_ = h2
 }
+
+func TestCardSet(t *testing.T) {
+	for i := 0; i < NumCards; i++ {
+		if got := CardAt(i).Index(); got != i {
+			t.Fatalf("CardAt(%d).Index() = %d", i, got)
+		}
+	}
+	cards, _ := ParseCards([]string{"S2", "HA", "DT"})
+	s := CardSetOf(cards)
+	if s.Count() != 3 || !s.Contains(cards[1]) {
+		t.Fatalf("CardSetOf(%v) = %v", cards, s)
+	}
+	s.Remove(cards[1])
+	if s.Contains(cards[1]) || s.Count() != 2 {
+		t.Errorf("after Remove(%v): %v", cards[1], s)
+	}
+	s.Add(cards[1])
+	if got := s.String(); got != "[HA DT S2]" {
+		t.Errorf("String() = %q, want %q", got, "[HA DT S2]")
+	}
+	if got := CardSetOf(s.Cards()); got != s {
+		t.Errorf("round trip = %v, want %v", got, s)
+	}
+}
+
+func TestParseCards_Duplicate(t *testing.T) {
+	_, err := ParseCards([]string{"HA", "ha"})
+	if _, ok := err.(*DuplicateCardError); !ok {
+		t.Fatalf("err = %v, want DuplicateCardError", err)
+	}
+}
+
+func TestShowdown(t *testing.T) {
+	board, _ := ParseCards([]string{"HK", "D8", "C8", "S3", "H2"})
+	players := make([][]Card, 3)
+	players[0], _ = ParseCards([]string{"SA", "D4"})
+	players[1], _ = ParseCards([]string{"CK", "DK"})
+	players[2], _ = ParseCards([]string{"HA", "C4"})
+	res, err := Showdown(board, players)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Winners) != 1 || res.Winners[0] != 1 {
+		t.Errorf("winners = %v, want [1]", res.Winners)
+	}
+	if want := []int{1, 0, 2}; fmt.Sprint(res.Ranking) != fmt.Sprint(want) {
+		t.Errorf("ranking = %v, want %v", res.Ranking, want)
+	}
+
+	// Same board plays for everyone: a three-way split.
+	board, _ = ParseCards([]string{"HA", "HK", "HQ", "HJ", "HT"})
+	players[1], _ = ParseCards([]string{"C5", "D5"})
+	players[2], _ = ParseCards([]string{"C9", "C4"})
+	res, err = Showdown(board, players)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Split() || len(res.Winners) != 3 {
+		t.Errorf("winners = %v, want all three", res.Winners)
+	}
+
+	players[2], _ = ParseCards([]string{"SA", "C3"})
+	if _, err := Showdown(board, players); err == nil {
+		t.Error("want error for a card dealt to two players")
+	}
+}
+
+func TestExactOdds(t *testing.T) {
+	hole, _ := ParseCards([]string{"HA", "HK"})
+	community, _ := ParseCards([]string{"HQ", "HJ", "HT", "S2", "D3"})
+	odds, err := ExactEquity(hole, community, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if odds.Win != 1 || odds.Equity != 1 || odds.Samples != 990 {
+		t.Errorf("royal flush on the river: %+v, want Win=1 over 990 deals", odds)
+	}
+
+	// Flush draw plus overcards on the turn, checked against Monte Carlo.
+	hole, _ = ParseCards([]string{"SA", "SK"})
+	community, _ = ParseCards([]string{"S7", "S2", "D9", "CT"})
+	odds, err = ExactEquity(hole, community, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (SimConfig{Hole: hole, Community: community, NumPlayers: 2}).ExactDeals(); odds.Samples != want {
+		t.Errorf("deals = %d, want %d", odds.Samples, want)
+	}
+	if sum := odds.Win + odds.Tie + odds.Loss; math.Abs(sum-1) > 1e-9 {
+		t.Errorf("win+tie+loss = %v, want 1", sum)
+	}
+	mc, err := WinProbability(hole, community, 2, 100000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(mc.Win-odds.Win) > 4*mc.WinStdErr || math.Abs(mc.Equity-odds.Equity) > 4*mc.EquityStdErr {
+		t.Errorf("Monte Carlo %+v too far from exact %+v", mc, odds)
+	}
+}
+
+func TestExactEquity_SplitPot(t *testing.T) {
+	// The board plays for everyone: every deal is a three-way split.
+	hole, _ := ParseCards([]string{"C2", "C3"})
+	community, _ := ParseCards([]string{"HA", "HK", "HQ", "HJ", "HT"})
+	odds, err := ExactEquity(hole, community, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if odds.Tie != 1 || math.Abs(odds.Equity-1.0/3) > 1e-12 {
+		t.Errorf("got %+v, want Tie=1 Equity=1/3", odds)
+	}
+}
+
+func TestEnumerate_DeadCards(t *testing.T) {
+	hole, _ := ParseCards([]string{"SA", "SK"})
+	community, _ := ParseCards([]string{"S7", "S2", "D9", "CT"})
+	live, err := Enumerate(context.Background(), SimConfig{Hole: hole, Community: community, NumPlayers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// With every other spade out of play the flush draw is dead.
+	dead, _ := ParseCards([]string{"S3", "S4", "S5", "S6", "S8", "S9", "ST", "SJ", "SQ"})
+	cfg := SimConfig{Hole: hole, Community: community, Dead: dead, NumPlayers: 2}
+	odds, err := Enumerate(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := choose(37, 2) * 35; odds.Samples != want || cfg.ExactDeals() != want {
+		t.Errorf("deals = %d (bound %d), want %d", odds.Samples, cfg.ExactDeals(), want)
+	}
+	if odds.Equity >= live.Equity {
+		t.Errorf("equity with dead spades %v, want below %v", odds.Equity, live.Equity)
+	}
+
+	cfg.NumSims, cfg.Seed = 50000, 5
+	mc, err := Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(mc.Equity-odds.Equity) > 4*mc.EquityStdErr {
+		t.Errorf("Monte Carlo %+v too far from exact %+v", mc, odds)
+	}
+
+	cfg.Dead = append(cfg.Dead, community[0])
+	if _, err := Enumerate(context.Background(), cfg); err == nil {
+		t.Error("dead card on the board: want an error")
+	}
+}
+
+func TestSimulate_DeterministicAcrossWorkers(t *testing.T) {
+	hole, _ := ParseCards([]string{"DQ", "CJ"})
+	cfg := SimConfig{Hole: hole, NumPlayers: 4, NumSims: 3*simChunk + 17, Seed: 42}
+	var first EquityResult
+	for _, workers := range []int{1, 2, 3, 8} {
+		cfg.Workers = workers
+		res, err := Simulate(context.Background(), cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if workers == 1 {
+			first = res
+		} else if !reflect.DeepEqual(res, first) {
+			t.Errorf("workers=%d: %+v, want %+v", workers, res, first)
+		}
+	}
+	if first.Samples != int64(cfg.NumSims) {
+		t.Errorf("samples = %d, want %d", first.Samples, cfg.NumSims)
+	}
+}
+
+func TestSimulate_Cancelled(t *testing.T) {
+	hole, _ := ParseCards([]string{"DQ", "CJ"})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Simulate(ctx, SimConfig{Hole: hole, NumPlayers: 2, NumSims: 1000000})
+	if err != context.Canceled {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestSimulate_ReportsSeed(t *testing.T) {
+	hole, _ := ParseCards([]string{"S9", "S8"})
+	community, _ := ParseCards([]string{"S7", "D2", "HK"})
+	cfg := SimConfig{Hole: hole, Community: community, NumPlayers: 3, NumSims: 5000}
+	first, err := Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Seed == 0 {
+		t.Fatal("result does not report the seed it picked")
+	}
+	cfg.Seed = first.Seed
+	again, err := Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(again, first) {
+		t.Errorf("replay with seed %d = %+v, want %+v", cfg.Seed, again, first)
+	}
+}
+
+func TestNewSeed_FitsADouble(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		if seed := NewSeed(); seed < 1 || seed > 1<<53-1 || int64(float64(seed)) != seed {
+			t.Fatalf("seed %d does not survive a float64", seed)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PotConfig describes the chips in a finished hand.
+type PotConfig struct {
+	Contributions []int64 // chips each player put in this hand
+	Folded        []bool  // players out of the hand; nil when nobody folded
+	Ranking       [][]int // showdown tiers of tied players, best first; see ShowdownResult.Tiers
+	LowRanking    [][]int // hi-lo tiers of players with a qualifying low, best first
+	Button        int     // odd chips go to winners clockwise from the player after the button
+}
+
+// Pot is the main pot or a side pot.
+type Pot struct {
+	Amount     int64   `json:"amount"`
+	Eligible   []int   `json:"eligible"` // players who may win it
+	Winners    []int   `json:"winners"`  // players sharing it, or its high half
+	LowWinners []int   `json:"low_winners,omitempty"`
+	Payouts    []int64 `json:"payouts"` // per player
+}
+
+// PotResult is how a hand's chips are paid out.
+type PotResult struct {
+	Pots    []Pot   `json:"pots"`    // main pot first
+	Refunds []int64 `json:"refunds"` // uncalled chips handed back, per player
+	Payouts []int64 `json:"payouts"` // everything each player collects, refunds included
+}
+
+// SplitPots layers the contributions into a main pot and side pots at each
+// all-in amount and pays every pot to its best eligible hands. Chips nobody
+// called are refunded; chips of folded players go to the pots they reached.
+// A split pot's odd chips go one each to the winners closest clockwise to
+// the button, and in hi-lo the odd chip between the halves goes high.
+// Ranking may be nil when only one player is left in.
+func SplitPots(cfg PotConfig) (PotResult, error) {
+	n := len(cfg.Contributions)
+	if cfg.Folded != nil && len(cfg.Folded) != n {
+		return PotResult{}, &InvalidInputError{Msg: "need a fold status per player"}
+	}
+	live := func(p int) bool { return cfg.Folded == nil || !cfg.Folded[p] }
+	players := 0
+	for p, c := range cfg.Contributions {
+		if c < 0 {
+			return PotResult{}, &InvalidInputError{Msg: fmt.Sprintf("player %d: negative contribution", p)}
+		}
+		if live(p) {
+			players++
+		}
+	}
+	if players == 0 {
+		return PotResult{}, &InvalidInputError{Msg: "every player folded"}
+	}
+	rank, err := tierIndex(cfg.Ranking, n)
+	if err != nil {
+		return PotResult{}, err
+	}
+	lowRank, err := tierIndex(cfg.LowRanking, n)
+	if err != nil {
+		return PotResult{}, err
+	}
+	for p := 0; p < n && players > 1; p++ {
+		if live(p) && rank[p] < 0 {
+			return PotResult{}, &InvalidInputError{Msg: fmt.Sprintf("player %d is live but not ranked", p)}
+		}
+	}
+
+	// Nobody called the part of the largest contribution above the next.
+	res := PotResult{Refunds: make([]int64, n), Payouts: make([]int64, n)}
+	contrib := append([]int64(nil), cfg.Contributions...)
+	top, second := 0, int64(0)
+	for p := 1; p < n; p++ {
+		if contrib[p] > contrib[top] {
+			top, second = p, contrib[top]
+		} else if contrib[p] > second {
+			second = contrib[p]
+		}
+	}
+	if extra := contrib[top] - second; extra > 0 {
+		contrib[top] = second
+		res.Refunds[top] = extra
+		res.Payouts[top] = extra
+	}
+
+	var levels []int64
+	for p, c := range contrib {
+		if live(p) {
+			levels = append(levels, c)
+		}
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	prev := int64(0)
+	for i, level := range levels {
+		last := i == len(levels)-1
+		if level == prev && !last {
+			continue
+		}
+		pot := Pot{Payouts: make([]int64, n)}
+		for p, c := range contrib {
+			if last {
+				// Folded chips above the last live level play here too.
+				pot.Amount += c - min(c, prev)
+			} else {
+				pot.Amount += min(c, level) - min(c, prev)
+			}
+			if live(p) && c >= level {
+				pot.Eligible = append(pot.Eligible, p)
+			}
+		}
+		prev = level
+		if pot.Amount == 0 {
+			continue
+		}
+		pot.Winners = pot.Eligible
+		if players > 1 {
+			pot.Winners = bestOf(pot.Eligible, rank)
+		}
+		pot.LowWinners = bestOf(pot.Eligible, lowRank)
+		high := pot.Amount
+		if len(pot.LowWinners) > 0 {
+			low := pot.Amount / 2
+			high -= low
+			splitChips(pot.Payouts, low, pot.LowWinners, cfg.Button)
+		}
+		splitChips(pot.Payouts, high, pot.Winners, cfg.Button)
+		for p, won := range pot.Payouts {
+			res.Payouts[p] += won
+		}
+		res.Pots = append(res.Pots, pot)
+	}
+	return res, nil
+}
+
+// tierIndex maps each player to the position of its tier, -1 if unranked.
+func tierIndex(tiers [][]int, n int) ([]int, error) {
+	index := make([]int, n)
+	for p := range index {
+		index[p] = -1
+	}
+	for t, tier := range tiers {
+		for _, p := range tier {
+			if p < 0 || p >= n {
+				return nil, &InvalidInputError{Msg: fmt.Sprintf("ranking names unknown player %d", p)}
+			}
+			if index[p] >= 0 {
+				return nil, &InvalidInputError{Msg: fmt.Sprintf("ranking names player %d twice", p)}
+			}
+			index[p] = t
+		}
+	}
+	return index, nil
+}
+
+// bestOf returns the players in the best tier among eligible, or nil when
+// none of them is ranked.
+func bestOf(eligible, rank []int) []int {
+	best := -1
+	for _, p := range eligible {
+		if r := rank[p]; r >= 0 && (best < 0 || r < best) {
+			best = r
+		}
+	}
+	var winners []int
+	for _, p := range eligible {
+		if best >= 0 && rank[p] == best {
+			winners = append(winners, p)
+		}
+	}
+	return winners
+}
+
+// splitChips divides amount evenly between winners into payouts, handing
+// odd chips one each to the winners closest clockwise to the button.
+func splitChips(payouts []int64, amount int64, winners []int, button int) {
+	share, odd := amount/int64(len(winners)), amount%int64(len(winners))
+	for _, p := range winners {
+		payouts[p] += share
+	}
+	for i := 1; odd > 0; i++ {
+		p := (button + i) % len(payouts)
+		for _, w := range winners {
+			if w == p {
+				payouts[p]++
+				odd--
+			}
+		}
+	}
+}
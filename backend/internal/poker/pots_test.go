@@ -0,0 +1,107 @@
+package poker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitPots_SidePots(t *testing.T) {
+	// Player 0 is all in for 50, player 1 for 100, player 2 covers and folds
+	// after putting in 150; player 3 bet 300 and nobody called the last 150.
+	res, err := SplitPots(PotConfig{
+		Contributions: []int64{50, 100, 150, 300},
+		Folded:        []bool{false, false, true, false},
+		Ranking:       [][]int{{0}, {1}, {3}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Pot{
+		{Amount: 200, Eligible: []int{0, 1, 3}, Winners: []int{0}, Payouts: []int64{200, 0, 0, 0}},
+		{Amount: 150, Eligible: []int{1, 3}, Winners: []int{1}, Payouts: []int64{0, 150, 0, 0}},
+		{Amount: 100, Eligible: []int{3}, Winners: []int{3}, Payouts: []int64{0, 0, 0, 100}},
+	}
+	if !reflect.DeepEqual(res.Pots, want) {
+		t.Errorf("pots = %+v, want %+v", res.Pots, want)
+	}
+	if !reflect.DeepEqual(res.Refunds, []int64{0, 0, 0, 150}) || !reflect.DeepEqual(res.Payouts, []int64{200, 150, 0, 250}) {
+		t.Errorf("refunds %v payouts %v", res.Refunds, res.Payouts)
+	}
+}
+
+func TestSplitPots_FoldedToOne(t *testing.T) {
+	res, err := SplitPots(PotConfig{Contributions: []int64{1, 2, 6}, Folded: []bool{true, true, false}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.Refunds, []int64{0, 0, 4}) || !reflect.DeepEqual(res.Payouts, []int64{0, 0, 9}) {
+		t.Errorf("refunds %v payouts %v, want 4 back and 5 won", res.Refunds, res.Payouts)
+	}
+	if len(res.Pots) != 1 || res.Pots[0].Amount != 5 || !reflect.DeepEqual(res.Pots[0].Winners, []int{2}) {
+		t.Errorf("pots = %+v", res.Pots)
+	}
+}
+
+func TestSplitPots_OddChips(t *testing.T) {
+	// Players 0 and 2 tie; the odd chip goes to the first clockwise from
+	// the button, player 2.
+	res, err := SplitPots(PotConfig{
+		Contributions: []int64{2, 1, 2},
+		Folded:        []bool{false, true, false},
+		Ranking:       [][]int{{0, 2}},
+		Button:        1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.Payouts, []int64{2, 0, 3}) {
+		t.Errorf("payouts = %v, want the odd chip to player 2", res.Payouts)
+	}
+
+	// Hi-lo: the odd chip between the halves goes high.
+	res, err = SplitPots(PotConfig{
+		Contributions: []int64{3, 3, 3},
+		Ranking:       [][]int{{0}, {1, 2}},
+		LowRanking:    [][]int{{1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.Payouts, []int64{5, 4, 0}) {
+		t.Errorf("payouts = %v, want 5 high and 4 low", res.Payouts)
+	}
+}
+
+func TestSplitPots_Invalid(t *testing.T) {
+	for _, cfg := range []PotConfig{
+		{Contributions: []int64{-1, 2}, Ranking: [][]int{{0}, {1}}},
+		{Contributions: []int64{2, 2}, Folded: []bool{true}},
+		{Contributions: []int64{2, 2}, Folded: []bool{true, true}},
+		{Contributions: []int64{2, 2}, Ranking: [][]int{{0}}},
+		{Contributions: []int64{2, 2}, Ranking: [][]int{{0}, {0, 1}}},
+		{Contributions: []int64{2, 2}, Ranking: [][]int{{0, 2}}},
+	} {
+		if _, err := SplitPots(cfg); !IsInvalidInput(err) {
+			t.Errorf("%+v: err = %v, want invalid input", cfg, err)
+		}
+	}
+}
+
+func TestShowdownResult_Tiers(t *testing.T) {
+	board, _ := ParseCards([]string{"H2", "D7", "C9", "SJ", "HK"})
+	var players [][]Card
+	for _, h := range [][]string{{"S3", "D4"}, {"SA", "DA"}, {"C3", "H4"}, {"CK", "DQ"}} {
+		cards, _ := ParseCards(h)
+		players = append(players, cards)
+	}
+	res, err := Showdown(board, players)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]int{{1}, {3}, {0, 2}}; !reflect.DeepEqual(res.Tiers(), want) {
+		t.Errorf("tiers = %v, want %v", res.Tiers(), want)
+	}
+	if res.LowTiers() != nil {
+		t.Errorf("Hold'em low tiers = %v", res.LowTiers())
+	}
+}
@@ -0,0 +1,261 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Combo is one specific set of hole cards with a relative weight.
+type Combo struct {
+	Cards  []Card  `json:"cards"`
+	Weight float64 `json:"weight"`
+}
+
+// Range is a weighted set of distinct hole-card combos, e.g. "QQ+, AKs".
+// Range notation describes Hold'em hands; other variants use known hands.
+type Range []Combo
+
+// HandRange returns the one-combo range of a known hand.
+func HandRange(cards ...Card) Range {
+	return Range{{Cards: cards, Weight: 1}}
+}
+
+// ParseRange parses comma-separated range notation:
+//
+//	QQ  pair (6 combos)        QQ+  QQ, KK, AA      22-55  pairs 22 to 55
+//	AKs suited (4 combos)      AKo  offsuit (12)    AK     both (16)
+//	ATs+ AT to AK suited       A2s-A5s  A2s to A5s
+//	AhKh or HAHK  an explicit combo (rank-suit or this API's suit-rank cards)
+//
+// Any entry may end in ":w" to give its combos weight w (default 1). A combo
+// named twice keeps the last weight; weight 0 removes it.
+func ParseRange(s string) (Range, error) {
+	weights := make(map[[2]int]float64)
+	var order [][2]int
+	for _, tok := range strings.Split(s, ",") {
+		tok = strings.TrimSpace(tok)
+		if tok == "" {
+			continue
+		}
+		weight := 1.0
+		if i := strings.IndexByte(tok, ':'); i >= 0 {
+			w, err := strconv.ParseFloat(tok[i+1:], 64)
+			if err != nil || w < 0 || w > 1 {
+				return nil, &InvalidInputError{Msg: fmt.Sprintf("range %q: weight must be 0-1", tok)}
+			}
+			weight, tok = w, strings.TrimSpace(tok[:i])
+		}
+		combos, err := parseRangeToken(tok)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range combos {
+			key := comboKey(c)
+			if _, ok := weights[key]; !ok {
+				order = append(order, key)
+			}
+			weights[key] = weight
+		}
+	}
+
+	r := make(Range, 0, len(order))
+	for _, key := range order {
+		if w := weights[key]; w > 0 {
+			r = append(r, Combo{Cards: []Card{CardAt(key[0]), CardAt(key[1])}, Weight: w})
+		}
+	}
+	if len(r) == 0 {
+		return nil, &InvalidInputError{Msg: fmt.Sprintf("range %q has no combos", s)}
+	}
+	return r, nil
+}
+
+// comboKey identifies a combo independently of card order.
+func comboKey(c [2]Card) [2]int {
+	a, b := c[0].Index(), c[1].Index()
+	if a > b {
+		a, b = b, a
+	}
+	return [2]int{a, b}
+}
+
+func parseRangeToken(tok string) ([][2]Card, error) {
+	bad := func() error {
+		return &InvalidInputError{Msg: fmt.Sprintf("invalid range entry %q", tok)}
+	}
+	up := strings.ToUpper(tok)
+
+	if len(up) == 4 && isSuitChar(up[0]) {
+		return explicitCombo(tok, up[0:2], up[2:4])
+	}
+	if len(up) == 4 && isSuitChar(up[1]) {
+		return explicitCombo(tok, string([]byte{up[1], up[0]}), string([]byte{up[3], up[2]}))
+	}
+
+	if lo, hi, ok := strings.Cut(up, "-"); ok {
+		a, err := parseHandClass(lo)
+		if err != nil {
+			return nil, bad()
+		}
+		b, err := parseHandClass(hi)
+		if err != nil {
+			return nil, bad()
+		}
+		if a.pair() != b.pair() || a.suit != b.suit || (!a.pair() && a.high != b.high) {
+			return nil, bad()
+		}
+		var combos [][2]Card
+		if a.pair() {
+			from, to := a.high, b.high
+			if from > to {
+				from, to = to, from
+			}
+			for r := from; r <= to; r++ {
+				combos = append(combos, handClass{high: r, low: r}.combos()...)
+			}
+			return combos, nil
+		}
+		from, to := a.low, b.low
+		if from > to {
+			from, to = to, from
+		}
+		for k := from; k <= to; k++ {
+			combos = append(combos, handClass{high: a.high, low: k, suit: a.suit}.combos()...)
+		}
+		return combos, nil
+	}
+
+	plus := strings.HasSuffix(up, "+")
+	hc, err := parseHandClass(strings.TrimSuffix(up, "+"))
+	if err != nil {
+		return nil, bad()
+	}
+	if !plus {
+		return hc.combos(), nil
+	}
+	var combos [][2]Card
+	if hc.pair() {
+		for r := hc.high; r <= RankA; r++ {
+			combos = append(combos, handClass{high: r, low: r}.combos()...)
+		}
+		return combos, nil
+	}
+	for k := hc.low; k < hc.high; k++ {
+		combos = append(combos, handClass{high: hc.high, low: k, suit: hc.suit}.combos()...)
+	}
+	return combos, nil
+}
+
+func isSuitChar(b byte) bool {
+	return b == SuitHearts || b == SuitDiamonds || b == SuitClubs || b == SuitSpades
+}
+
+func explicitCombo(tok, a, b string) ([][2]Card, error) {
+	cards, err := ParseCards([]string{a, b})
+	if err != nil {
+		return nil, &InvalidInputError{Msg: fmt.Sprintf("range entry %q: %v", tok, err)}
+	}
+	return [][2]Card{{cards[0], cards[1]}}, nil
+}
+
+// handClass is a starting hand without suits, e.g. AKs, T9o, QQ.
+type handClass struct {
+	high, low int
+	suit      byte // 'S' suited, 'O' offsuit, 0 either
+}
+
+func (h handClass) pair() bool { return h.high == h.low }
+
+func parseHandClass(s string) (handClass, error) {
+	if len(s) < 2 || len(s) > 3 {
+		return handClass{}, fmt.Errorf("bad hand class %q", s)
+	}
+	hi, ok1 := charToRank[s[0]]
+	lo, ok2 := charToRank[s[1]]
+	if !ok1 || !ok2 {
+		return handClass{}, fmt.Errorf("bad hand class %q", s)
+	}
+	if hi < lo {
+		hi, lo = lo, hi
+	}
+	h := handClass{high: hi, low: lo}
+	if len(s) == 3 {
+		if (s[2] != 'S' && s[2] != 'O') || h.pair() {
+			return handClass{}, fmt.Errorf("bad hand class %q", s)
+		}
+		h.suit = s[2]
+	}
+	return h, nil
+}
+
+func (h handClass) combos() [][2]Card {
+	var combos [][2]Card
+	for i, s1 := range indexToSuit {
+		for j, s2 := range indexToSuit {
+			switch {
+			case h.pair() && j <= i:
+				continue
+			case h.suit == 'S' && i != j, h.suit == 'O' && i == j:
+				continue
+			}
+			combos = append(combos, [2]Card{{Suit: s1, Rank: h.high}, {Suit: s2, Rank: h.low}})
+		}
+	}
+	return combos
+}
+
+// String writes the range back as explicit weighted combos.
+func (r Range) String() string {
+	parts := make([]string, len(r))
+	for i, c := range r {
+		for _, card := range c.Cards {
+			parts[i] += card.String()
+		}
+		if c.Weight != 1 {
+			parts[i] += ":" + strconv.FormatFloat(c.Weight, 'g', -1, 64)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Without returns the combos that share no card with dead.
+func (r Range) Without(dead CardSet) Range {
+	out := make(Range, 0, len(r))
+	for _, c := range r {
+		if CardSetOf(c.Cards)&dead == 0 {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// rangeSampler draws combos in proportion to their weights.
+type rangeSampler struct {
+	combos []Combo
+	cum    []float64 // running weight totals
+}
+
+func newRangeSampler(r Range) *rangeSampler {
+	s := &rangeSampler{combos: r, cum: make([]float64, len(r))}
+	total := 0.0
+	for i, c := range r {
+		total += c.Weight
+		s.cum[i] = total
+	}
+	return s
+}
+
+// sample maps u in [0, 1) to a combo.
+func (s *rangeSampler) sample(u float64) Combo {
+	x := u * s.cum[len(s.cum)-1]
+	i := sort.SearchFloat64s(s.cum, x)
+	if i < len(s.cum) && s.cum[i] == x {
+		i++
+	}
+	if i >= len(s.combos) {
+		i = len(s.combos) - 1
+	}
+	return s.combos[i]
+}
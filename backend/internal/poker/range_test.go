@@ -0,0 +1,102 @@
+package poker
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"QQ", 6},
+		{"QQ+", 18},
+		{"22-55", 24},
+		{"55-22", 24},
+		{"AKs", 4},
+		{"AKo", 12},
+		{"AK", 16},
+		{"ATs+", 16},
+		{"K9o+", 48},
+		{"A2s-A5s", 16},
+		{"AhKh", 1},
+		{"HAHK", 1},
+		{"QQ+, AKs, ATs+", 18 + 16},
+		{"AA, AhAd:0", 5},
+	}
+	for _, tt := range tests {
+		r, err := ParseRange(tt.in)
+		if err != nil {
+			t.Errorf("ParseRange(%q): %v", tt.in, err)
+			continue
+		}
+		if len(r) != tt.want {
+			t.Errorf("ParseRange(%q) has %d combos, want %d", tt.in, len(r), tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "AKx", "AAs", "AA-KQ", "A2s-K5s", "AK:2", "HAHA", "ZZ"} {
+		if _, err := ParseRange(in); err == nil {
+			t.Errorf("ParseRange(%q): want error", in)
+		}
+	}
+}
+
+func TestParseRange_Weights(t *testing.T) {
+	r, err := ParseRange("KK:0.5, KhKs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range r {
+		want := 0.5
+		if comboKey([2]Card(c.Cards)) == comboKey([2]Card{{SuitHearts, RankK}, {SuitSpades, RankK}}) {
+			want = 1
+		}
+		if c.Weight != want {
+			t.Errorf("%v weight = %v, want %v", c.Cards, c.Weight, want)
+		}
+	}
+}
+
+func TestSimulate_AgainstRange(t *testing.T) {
+	hole, _ := ParseCards([]string{"SA", "HA"})
+	kings, _ := ParseRange("KK")
+	res, err := Simulate(context.Background(), SimConfig{
+		Hole:       hole,
+		NumPlayers: 2,
+		NumSims:    50000,
+		Ranges:     []Range{kings},
+		Seed:       7,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// AA is about an 82% favourite over KK preflop.
+	if math.Abs(res.Equity-0.82) > 0.01 {
+		t.Errorf("AA vs KK equity = %.4f, want about 0.82", res.Equity)
+	}
+
+	// Card removal: with both red kings known, the range is KsKc only.
+	community, _ := ParseCards([]string{"HK", "DK", "C2"})
+	if _, err := Simulate(context.Background(), SimConfig{
+		Hole:       hole,
+		Community:  community,
+		NumPlayers: 2,
+		NumSims:    1000,
+		Ranges:     []Range{kings},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	aces, _ := ParseRange("AA")
+	if _, err := Simulate(context.Background(), SimConfig{
+		Hole:       hole,
+		NumPlayers: 3,
+		NumSims:    1000,
+		Ranges:     []Range{aces, aces},
+	}); err == nil {
+		t.Error("want error when two opponents need the last two aces")
+	}
+}
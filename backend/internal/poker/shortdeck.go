@@ -0,0 +1,33 @@
+package poker
+
+// shortDeck holds the 36 cards from six to ace of each suit.
+const shortDeck CardSet = 0x1FF0 | 0x1FF0<<numRanks | 0x1FF0<<(2*numRanks) | 0x1FF0<<(3*numRanks)
+
+// shortStraight is A-6-7-8-9 as a suit mask: the lowest short-deck straight,
+// with the ace playing below the six.
+const shortStraight uint16 = 1<<(RankA-Rank2) | 0xF<<(Rank6-Rank2)
+
+// shortDeckFlushBit lifts flushes and every category above a full house
+// over full houses in short-deck values, where a flush beats a full house.
+// HandValue.Rank ignores it.
+const shortDeckFlushBit HandValue = 1 << 24
+
+// shortDeckValue scores 5 to 7 cards under short-deck rules.
+func shortDeckValue(s CardSet) HandValue {
+	v := evaluateSet(s)
+	var ranks uint16
+	for i := 0; i < 4; i++ {
+		m := s.suitMask(i)
+		if m&shortStraight == shortStraight && v.Rank() < StraightFlush {
+			v = makeValue(StraightFlush, [5]int{Rank9, Rank8, Rank7, Rank6, 1})
+		}
+		ranks |= m
+	}
+	if ranks&shortStraight == shortStraight && v.Rank() < Straight {
+		v = makeValue(Straight, [5]int{Rank9, Rank8, Rank7, Rank6, 1})
+	}
+	if r := v.Rank(); r == Flush || r > FullHouse {
+		v |= shortDeckFlushBit
+	}
+	return v
+}
@@ -0,0 +1,125 @@
+package poker
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// shortDeckFive scores exactly five cards by the short-deck rules directly.
+func shortDeckFive(cards []Card) HandValue {
+	v := handValue(combinatorialBest(cards))
+	set := CardSetOf(cards)
+	var ranks uint16
+	for i := 0; i < 4; i++ {
+		ranks |= set.suitMask(i)
+	}
+	if ranks == shortStraight {
+		if v.Rank() == Flush {
+			v = makeValue(StraightFlush, [5]int{Rank9, Rank8, Rank7, Rank6, 1})
+		} else {
+			v = makeValue(Straight, [5]int{Rank9, Rank8, Rank7, Rank6, 1})
+		}
+	}
+	if r := v.Rank(); r == Flush || r > FullHouse {
+		v |= shortDeckFlushBit
+	}
+	return v
+}
+
+func TestShortDeck_MatchesCombinatorial(t *testing.T) {
+	deck := shortDeck.Cards()
+	rng := rand.New(rand.NewSource(6))
+	for i := 0; i < 20000; i++ {
+		rng.Shuffle(len(deck), func(a, b int) { deck[a], deck[b] = deck[b], deck[a] })
+		hand := deck[:7]
+		var want HandValue
+		combine(hand, 0, 5, nil, func(five []Card) {
+			if v := shortDeckFive(five); v > want {
+				want = v
+			}
+		})
+		h, err := ShortDeck.EvaluateBestHand(hand[:2], hand[2:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Value != want || shortDeckFive(h.BestHand) != want {
+			t.Fatalf("%v: got %x %v, want %x", hand, h.Value, h.BestHand, want)
+		}
+	}
+}
+
+func TestShortDeck_Rules(t *testing.T) {
+	eval := func(v Variant, hole, board []string) EvaluatedHand {
+		t.Helper()
+		h, err := ParseCards(hole)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := ParseCards(board)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := v.EvaluateBestHand(h, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	low := eval(ShortDeck, []string{"HA", "D6"}, []string{"C7", "S8", "H9", "DK", "CK"})
+	if low.Rank != Straight || low.BestHand[0].Rank != Rank9 || low.BestHand[4].Rank != RankA {
+		t.Errorf("A-6-7-8-9: %v %v, want a nine-high straight", low.RankName, low.BestHand)
+	}
+	if high := eval(ShortDeck, []string{"HT", "D6"}, []string{"C7", "S8", "H9", "DK", "CK"}); high.Value <= low.Value {
+		t.Errorf("6-T straight %x does not beat A-6-7-8-9 %x", high.Value, low.Value)
+	}
+	if sf := eval(ShortDeck, []string{"HA", "H6"}, []string{"H7", "H8", "H9", "DK", "CK"}); sf.Rank != StraightFlush {
+		t.Errorf("suited A-6-7-8-9: %v, want straight flush", sf.RankName)
+	}
+	if h := eval(Holdem, []string{"HA", "D6"}, []string{"C7", "S8", "H9", "DK", "CK"}); h.Rank != OnePair {
+		t.Errorf("Hold'em A-6-7-8-9: %v, want one pair", h.RankName)
+	}
+
+	for _, v := range []Variant{Holdem, ShortDeck} {
+		flush := eval(v, []string{"HA", "H6"}, []string{"H7", "HJ", "HQ", "DK", "CK"})
+		boat := eval(v, []string{"SK", "S6"}, []string{"D6", "HJ", "HQ", "DK", "CK"})
+		if flush.Rank != Flush || boat.Rank != FullHouse {
+			t.Fatalf("%v: %v vs %v", v, flush.RankName, boat.RankName)
+		}
+		if want := v == ShortDeck; (flush.Value > boat.Value) != want {
+			t.Errorf("%v: flush beats full house = %v, want %v", v, flush.Value > boat.Value, want)
+		}
+	}
+
+	if _, err := ShortDeck.EvaluateBestHand([]Card{{SuitHearts, RankA}, {SuitHearts, Rank5}}, nil); !IsInvalidInput(err) {
+		t.Errorf("short deck with a five: err = %v, want invalid input", err)
+	}
+}
+
+func TestShortDeck_Equity(t *testing.T) {
+	hole, _ := ParseCards([]string{"SA", "SK"})
+	community, _ := ParseCards([]string{"S7", "S6", "D9", "CT"})
+	cfg := SimConfig{Variant: ShortDeck, Hole: hole, Community: community, NumPlayers: 2}
+	exact, err := Enumerate(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := choose(30, 2) * 28; exact.Samples != want || cfg.ExactDeals() != want {
+		t.Errorf("deals = %d (bound %d), want %d", exact.Samples, cfg.ExactDeals(), want)
+	}
+	cfg.NumSims, cfg.Seed = 50000, 36
+	mc, err := Simulate(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(mc.Equity-exact.Equity) > 4*mc.EquityStdErr {
+		t.Errorf("Monte Carlo %+v too far from exact %+v", mc, exact)
+	}
+
+	cfg.Ranges = []Range{mustRange(t, "22-55")}
+	if _, err := Simulate(context.Background(), cfg); !IsInvalidInput(err) {
+		t.Errorf("range of removed cards: err = %v, want invalid input", err)
+	}
+}
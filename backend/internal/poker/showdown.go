@@ -0,0 +1,124 @@
+package poker
+
+import (
+	"sort"
+)
+
+// ShowdownResult holds the outcome of several players sharing one board.
+type ShowdownResult struct {
+	Hands   []EvaluatedHand `json:"hands"`   // per player, in input order
+	Ranking []int           `json:"ranking"` // player indices, best hand first; ties keep input order
+	Winners []int           `json:"winners"` // every player tied for the best hand
+
+	// LowWinners are the players tied for the best qualifying low in hi-lo
+	// variants; empty when no low qualifies.
+	LowWinners []int `json:"low_winners,omitempty"`
+}
+
+// Tiers groups the players into tiers of tied hands, best first: the
+// multi-way form of CompareHands, as SplitPots takes it.
+func (r ShowdownResult) Tiers() [][]int {
+	var tiers [][]int
+	for i, p := range r.Ranking {
+		if i == 0 || r.Hands[p].Value.Compare(r.Hands[r.Ranking[i-1]].Value) != 0 {
+			tiers = append(tiers, nil)
+		}
+		tiers[len(tiers)-1] = append(tiers[len(tiers)-1], p)
+	}
+	return tiers
+}
+
+// LowTiers groups the players with a qualifying low into tiers, best first.
+func (r ShowdownResult) LowTiers() [][]int {
+	var lows []int
+	for p, h := range r.Hands {
+		if h.Low != nil {
+			lows = append(lows, p)
+		}
+	}
+	sort.SliceStable(lows, func(a, b int) bool {
+		return r.Hands[lows[a]].Low.Value.Compare(r.Hands[lows[b]].Low.Value) > 0
+	})
+	var tiers [][]int
+	for i, p := range lows {
+		if i == 0 || r.Hands[p].Low.Value != r.Hands[lows[i-1]].Low.Value {
+			tiers = append(tiers, nil)
+		}
+		tiers[len(tiers)-1] = append(tiers[len(tiers)-1], p)
+	}
+	return tiers
+}
+
+// HiLo says who wins each half of the pot; without a low half the high
+// winners share it all.
+func (r ShowdownResult) HiLo() HiLoResult {
+	return HiLoResult{High: r.Winners, Low: r.LowWinners}
+}
+
+// Split reports whether the pot is shared rather than scooped.
+func (r ShowdownResult) Split() bool {
+	return !r.HiLo().Scoop()
+}
+
+// Shares returns each player's fraction of the pot.
+func (r ShowdownResult) Shares() []float64 {
+	return r.HiLo().Shares(len(r.Hands))
+}
+
+// Showdown evaluates each player's hole cards against the shared board.
+func Showdown(board []Card, players [][]Card) (ShowdownResult, error) {
+	return Holdem.Showdown(board, players)
+}
+
+// Showdown is Showdown under v's rules.
+func (v Variant) Showdown(board []Card, players [][]Card) (ShowdownResult, error) {
+	if len(players) == 0 {
+		return ShowdownResult{}, &InvalidInputError{Msg: "need at least 1 player"}
+	}
+	seen := CardSetOf(board)
+	if seen.Count() != len(board) {
+		return ShowdownResult{}, &InvalidInputError{Msg: "duplicate community card"}
+	}
+	res := ShowdownResult{
+		Hands:   make([]EvaluatedHand, len(players)),
+		Ranking: make([]int, len(players)),
+	}
+	for i, hole := range players {
+		for _, c := range hole {
+			if seen.Contains(c) {
+				return ShowdownResult{}, &DuplicateCardError{Card: c.String()}
+			}
+			seen.Add(c)
+		}
+		h, err := v.EvaluateBestHand(hole, board)
+		if err != nil {
+			return ShowdownResult{}, err
+		}
+		res.Hands[i] = h
+		res.Ranking[i] = i
+	}
+	sort.SliceStable(res.Ranking, func(a, b int) bool {
+		return res.Hands[res.Ranking[a]].Value > res.Hands[res.Ranking[b]].Value
+	})
+	best := res.Hands[res.Ranking[0]].Value
+	for _, i := range res.Ranking {
+		if res.Hands[i].Value != best {
+			break
+		}
+		res.Winners = append(res.Winners, i)
+	}
+	if v.HiLo() {
+		var bestLow LowValue
+		for _, h := range res.Hands {
+			if h.Low != nil && h.Low.Value.Compare(bestLow) > 0 {
+				bestLow = h.Low.Value
+			}
+		}
+		for i, h := range res.Hands {
+			if h.Low != nil && h.Low.Value == bestLow {
+				res.LowWinners = append(res.LowWinners, i)
+			}
+		}
+	}
+	return res, nil
+}
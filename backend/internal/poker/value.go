@@ -0,0 +1,100 @@
+package poker
+
+// HandValue is a totally ordered strength of a poker hand (higher = better).
+// Equal values tie. The layout is the RankType in bits 20+ followed by five
+// 4-bit rank slots, most significant first, holding the ranks in comparison
+// order: grouped ranks (quads, trips, pairs) by size and then by rank, then
+// kickers. In a wheel the Ace counts as 1, so A-5 loses to 6-high.
+// Short-deck values set a bit above the RankType to reorder categories.
+type HandValue uint32
+
+const valueRankShift = 20
+
+// Rank returns the hand category.
+func (v HandValue) Rank() RankType {
+	return RankType(v >> valueRankShift & 0xF)
+}
+
+// Compare returns 1 if v beats o, -1 if o beats v, 0 on a tie.
+func (v HandValue) Compare(o HandValue) int {
+	switch {
+	case v > o:
+		return 1
+	case v < o:
+		return -1
+	}
+	return 0
+}
+
+// handValue scores up to five cards. Straights and flushes need exactly five.
+func handValue(c []Card) HandValue {
+	var counts [RankA + 1]int
+	flush := len(c) == 5
+	for _, card := range c {
+		counts[card.Rank]++
+		if card.Suit != c[0].Suit {
+			flush = false
+		}
+	}
+
+	// Ranks in comparison order: largest group first, higher rank first.
+	var ranks [5]int
+	var groups [5]int
+	n, ng := 0, 0
+	for size := 4; size >= 1; size-- {
+		for r := RankA; r >= Rank2; r-- {
+			if counts[r] != size {
+				continue
+			}
+			groups[ng] = size
+			ng++
+			for i := 0; i < size; i++ {
+				ranks[n] = r
+				n++
+			}
+		}
+	}
+
+	straight := false
+	if ng == 5 {
+		if ranks[0]-ranks[4] == 4 {
+			straight = true
+		} else if ranks[0] == RankA && ranks[1] == Rank5 {
+			straight = true
+			ranks = [5]int{Rank5, Rank4, Rank3, Rank2, 1}
+		}
+	}
+
+	var rank RankType
+	switch {
+	case straight && flush && ranks[0] == RankA:
+		rank = RoyalFlush
+	case straight && flush:
+		rank = StraightFlush
+	case groups[0] == 4:
+		rank = FourOfAKind
+	case groups[0] == 3 && groups[1] == 2:
+		rank = FullHouse
+	case flush:
+		rank = Flush
+	case straight:
+		rank = Straight
+	case groups[0] == 3:
+		rank = ThreeOfAKind
+	case groups[0] == 2 && groups[1] == 2:
+		rank = TwoPair
+	case groups[0] == 2:
+		rank = OnePair
+	default:
+		rank = HighCard
+	}
+	return makeValue(rank, ranks)
+}
+
+func makeValue(rank RankType, ranks [5]int) HandValue {
+	v := HandValue(rank) << valueRankShift
+	for i, r := range ranks {
+		v |= HandValue(r) << (4 * (4 - i))
+	}
+	return v
+}
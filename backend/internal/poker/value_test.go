@@ -0,0 +1,121 @@
+package poker
+
+import (
+	"testing"
+)
+
+func allCards() []Card {
+	cards := make([]Card, 0, 52)
+	for _, s := range []byte{SuitHearts, SuitDiamonds, SuitClubs, SuitSpades} {
+		for r := Rank2; r <= RankA; r++ {
+			cards = append(cards, Card{Suit: s, Rank: r})
+		}
+	}
+	return cards
+}
+
+// TestHandValue_EquivalenceClasses checks all 2,598,960 five-card hands fall
+// into the 7,462 known equivalence classes with the known category counts.
+func TestHandValue_EquivalenceClasses(t *testing.T) {
+	wantHands := map[RankType]int{
+		RoyalFlush:    4,
+		StraightFlush: 36,
+		FourOfAKind:   624,
+		FullHouse:     3744,
+		Flush:         5108,
+		Straight:      10200,
+		ThreeOfAKind:  54912,
+		TwoPair:       123552,
+		OnePair:       1098240,
+		HighCard:      1302540,
+	}
+	wantClasses := map[RankType]int{
+		RoyalFlush:    1,
+		StraightFlush: 9,
+		FourOfAKind:   156,
+		FullHouse:     156,
+		Flush:         1277,
+		Straight:      10,
+		ThreeOfAKind:  858,
+		TwoPair:       858,
+		OnePair:       2860,
+		HighCard:      1277,
+	}
+
+	deck := allCards()
+	hands := make(map[RankType]int)
+	classes := make(map[HandValue]bool)
+	total := 0
+	hand := make([]Card, 5)
+	for a := 0; a < 52; a++ {
+		for b := a + 1; b < 52; b++ {
+			for c := b + 1; c < 52; c++ {
+				for d := c + 1; d < 52; d++ {
+					for e := d + 1; e < 52; e++ {
+						hand[0], hand[1], hand[2], hand[3], hand[4] = deck[a], deck[b], deck[c], deck[d], deck[e]
+						v := handValue(hand)
+						hands[v.Rank()]++
+						classes[v] = true
+						total++
+					}
+				}
+			}
+		}
+	}
+	if total != 2598960 {
+		t.Fatalf("enumerated %d hands, want 2598960", total)
+	}
+	if len(classes) != 7462 {
+		t.Errorf("got %d equivalence classes, want 7462", len(classes))
+	}
+	perRank := make(map[RankType]int)
+	for v := range classes {
+		perRank[v.Rank()]++
+	}
+	for r, want := range wantHands {
+		if hands[r] != want {
+			t.Errorf("%v: %d hands, want %d", r, hands[r], want)
+		}
+		if perRank[r] != wantClasses[r] {
+			t.Errorf("%v: %d classes, want %d", r, perRank[r], wantClasses[r])
+		}
+	}
+}
+
+func TestHandValue_Ordering(t *testing.T) {
+	tests := []struct {
+		name          string
+		better, worse []string
+	}{
+		{"pair beats lower pair with ace kicker", []string{"H4", "D4", "C2", "S3", "H5"}, []string{"H3", "D3", "CA", "SK", "HQ"}},
+		{"kicker decides equal pairs", []string{"H9", "D9", "CA", "S3", "H2"}, []string{"C9", "S9", "HK", "DQ", "CJ"}},
+		{"two pair compares top pair first", []string{"HK", "DK", "C2", "S2", "H3"}, []string{"HQ", "DQ", "CJ", "SJ", "HA"}},
+		{"full house compares trips first", []string{"H3", "D3", "C3", "S2", "H2"}, []string{"H2", "D2", "C2", "SA", "HA"}},
+		{"six-high straight beats the wheel", []string{"H2", "D3", "C4", "S5", "H6"}, []string{"HA", "D2", "C3", "S4", "H5"}},
+		{"steel wheel is the lowest straight flush", []string{"H2", "H3", "H4", "H5", "H6"}, []string{"SA", "S2", "S3", "S4", "S5"}},
+		{"quads kicker", []string{"H7", "D7", "C7", "S7", "HK"}, []string{"H7", "D7", "C7", "S7", "HQ"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			better, _ := ParseCards(tt.better)
+			worse, _ := ParseCards(tt.worse)
+			if got := handValue(better).Compare(handValue(worse)); got != 1 {
+				t.Errorf("Compare = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestCompareHands_PairBeforeKicker(t *testing.T) {
+	hole1, _ := ParseCards([]string{"H4", "D4"})
+	comm1, _ := ParseCards([]string{"C2", "S7", "D9", "CJ", "S8"})
+	hole2, _ := ParseCards([]string{"H3", "DA"})
+	comm2, _ := ParseCards([]string{"C3", "SK", "DQ", "HT", "S6"})
+	winner, _, _, err := CompareHands(hole1, comm1, hole2, comm2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if winner != 1 {
+		t.Errorf("winner = %d, want 1 (pair of 4s beats pair of 3s)", winner)
+	}
+}
@@ -0,0 +1,182 @@
+package poker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Variant is a community-card poker game: it fixes how many hole cards each
+// player holds and how a hand is made from them and the board. The zero
+// value is Hold'em.
+type Variant int
+
+const (
+	Holdem    Variant = iota // 2 hole cards; the best five of all seven
+	Omaha4                   // PLO4: 4 hole cards; exactly two of them with three board cards
+	Omaha5                   // PLO5: as Omaha4 with 5 hole cards
+	Omaha8                   // Omaha Hi-Lo: Omaha4 with the pot split with the best eight-or-better low
+	ShortDeck                // 6+ Hold'em: 36 cards, a flush beats a full house, A-6-7-8-9 is a straight
+)
+
+var variantNames = [...]string{
+	Holdem:    "holdem",
+	Omaha4:    "plo4",
+	Omaha5:    "plo5",
+	Omaha8:    "plo8",
+	ShortDeck: "6plus",
+}
+
+func (v Variant) String() string {
+	if !v.Valid() {
+		return "unknown"
+	}
+	return variantNames[v]
+}
+
+// ParseVariant looks a variant up by name; "" is Hold'em, "omaha" is PLO4,
+// "omaha8" is Omaha Hi-Lo and "shortdeck" is 6+.
+func ParseVariant(name string) (Variant, error) {
+	switch name = strings.ToLower(strings.TrimSpace(name)); name {
+	case "":
+		return Holdem, nil
+	case "omaha", "plo":
+		return Omaha4, nil
+	case "omaha8", "omaha-hi-lo", "o8":
+		return Omaha8, nil
+	case "shortdeck", "short-deck", "6+":
+		return ShortDeck, nil
+	}
+	for v, n := range variantNames {
+		if n == name {
+			return Variant(v), nil
+		}
+	}
+	return 0, &InvalidInputError{Msg: fmt.Sprintf("unknown variant %q", name)}
+}
+
+func (v Variant) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+func (v *Variant) UnmarshalText(b []byte) error {
+	parsed, err := ParseVariant(string(b))
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// HoleCards returns how many hole cards each player is dealt.
+func (v Variant) HoleCards() int {
+	switch v {
+	case Omaha4, Omaha8:
+		return 4
+	case Omaha5:
+		return 5
+	}
+	return 2
+}
+
+// Deck returns the cards v is played with.
+func (v Variant) Deck() CardSet {
+	if v == ShortDeck {
+		return shortDeck
+	}
+	return fullDeck
+}
+
+// checkDeck rejects cards v's deck does not hold.
+func (v Variant) checkDeck(cards []Card) error {
+	deck := v.Deck()
+	for _, c := range cards {
+		if !deck.Contains(c) {
+			return &InvalidInputError{Msg: fmt.Sprintf("%s is not in the %v deck", c, v)}
+		}
+	}
+	return nil
+}
+
+// Evaluations returns how many hands are scored per player and board: one
+// for Hold'em, every two hole cards with every three board cards for Omaha.
+// It scales the cost of a deal.
+func (v Variant) Evaluations() int {
+	if !v.omaha() {
+		return 1
+	}
+	return int(choose(v.HoleCards(), 2) * choose(5, 3))
+}
+
+// Valid reports whether v is a known variant.
+func (v Variant) Valid() bool {
+	return v >= 0 && int(v) < len(variantNames)
+}
+
+// HiLo reports whether the pot is split between the best high hand and the
+// best qualifying low.
+func (v Variant) HiLo() bool {
+	return v == Omaha8
+}
+
+// omaha reports whether hands use exactly two hole and three board cards.
+func (v Variant) omaha() bool {
+	return v == Omaha4 || v == Omaha5 || v == Omaha8
+}
+
+// value scores the best hand hole and board make under v's rules, from at
+// least five cards. Omaha boards need at least three cards.
+func (v Variant) value(hole, board CardSet) HandValue {
+	switch {
+	case v == ShortDeck:
+		return shortDeckValue(hole | board)
+	case v.omaha():
+		value, _ := omahaBest(hole, board)
+		return value
+	}
+	return evaluateSet(hole | board)
+}
+
+// low scores the best qualifying low hole and board make under v's rules,
+// or 0 when there is none or v has no low half.
+func (v Variant) low(hole, board CardSet) LowValue {
+	if !v.HiLo() {
+		return 0
+	}
+	low, _ := omahaLow(hole, board)
+	return low
+}
+
+// omahaBest tries every two hole cards with every three board cards and
+// returns the best value and the five cards that make it.
+func omahaBest(hole, board CardSet) (HandValue, CardSet) {
+	var hs, bs [5]CardSet
+	nh, nb := splitCards(hole, &hs), splitCards(board, &bs)
+	var best HandValue
+	var bestSet CardSet
+	for i := 0; i < nh; i++ {
+		for j := i + 1; j < nh; j++ {
+			two := hs[i] | hs[j]
+			for a := 0; a < nb; a++ {
+				for b := a + 1; b < nb; b++ {
+					for c := b + 1; c < nb; c++ {
+						s := two | bs[a] | bs[b] | bs[c]
+						if value := evaluateSet(s); value > best {
+							best, bestSet = value, s
+						}
+					}
+				}
+			}
+		}
+	}
+	return best, bestSet
+}
+
+// splitCards stores each card of s, up to five, as its own set.
+func splitCards(s CardSet, out *[5]CardSet) int {
+	n := 0
+	for ; s != 0 && n < len(out); s &= s - 1 {
+		out[n] = s & -s
+		n++
+	}
+	return n
+}
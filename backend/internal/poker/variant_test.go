@@ -0,0 +1,134 @@
+package poker
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+func TestParseVariant(t *testing.T) {
+	for name, want := range map[string]Variant{"": Holdem, "holdem": Holdem, "PLO4": Omaha4, "omaha": Omaha4, "plo5": Omaha5} {
+		if v, err := ParseVariant(name); err != nil || v != want {
+			t.Errorf("ParseVariant(%q) = %v, %v, want %v", name, v, err, want)
+		}
+	}
+	if _, err := ParseVariant("razz"); !IsInvalidInput(err) {
+		t.Errorf("ParseVariant(razz): err = %v, want invalid input", err)
+	}
+}
+
+func TestOmaha_ExactlyTwoHoleCards(t *testing.T) {
+	board, _ := ParseCards([]string{"H2", "H7", "H9", "HJ", "D3"})
+	tests := []struct {
+		hole []string
+		want RankType
+	}{
+		{[]string{"HA", "SA", "DK", "CK"}, OnePair},            // one heart: no flush
+		{[]string{"HA", "HK", "DK", "CK"}, Flush},              // two hearts
+		{[]string{"ST", "S8", "D4", "C5"}, Straight},           // T8 with J97
+		{[]string{"ST", "C2", "D4", "C5"}, OnePair},            // one card does not play the straight
+		{[]string{"S2", "D2", "C7", "S9", "DJ"}, ThreeOfAKind}, // PLO5
+	}
+	for _, tt := range tests {
+		hole, _ := ParseCards(tt.hole)
+		v := Omaha4
+		if len(hole) == 5 {
+			v = Omaha5
+		}
+		h, err := v.EvaluateBestHand(hole, board)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Rank != tt.want || len(h.BestHand) != 5 {
+			t.Errorf("%v %v: %v %v, want %v", v, tt.hole, h.RankName, h.BestHand, tt.want)
+		}
+		inHole := 0
+		for _, c := range h.BestHand {
+			if CardSetOf(hole).Contains(c) {
+				inHole++
+			}
+		}
+		if inHole != 2 {
+			t.Errorf("%v %v: best hand %v uses %d hole cards", v, tt.hole, h.BestHand, inHole)
+		}
+	}
+
+	if _, err := Omaha4.EvaluateBestHand(board[:4], board[4:]); !IsInvalidInput(err) {
+		t.Errorf("Omaha with 1 community card: err = %v, want invalid input", err)
+	}
+	if _, err := Omaha4.EvaluateBestHand(board[:2], board[2:]); !IsInvalidInput(err) {
+		t.Errorf("Omaha with 2 hole cards: err = %v, want invalid input", err)
+	}
+}
+
+func TestOmaha_MatchesCombinatorial(t *testing.T) {
+	for _, cards := range randomHands(5000, 9, 9) {
+		hole, board := cards[:4], cards[4:]
+		var want HandValue
+		combine(hole, 0, 2, nil, func(two []Card) {
+			combine(board, 0, 3, nil, func(three []Card) {
+				if v := handValue(combinatorialBest(append(two, three...))); v > want {
+					want = v
+				}
+			})
+		})
+		h, err := Omaha4.EvaluateBestHand(hole, board)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Value != want || handValue(h.BestHand) != want {
+			t.Fatalf("Omaha %v on %v = %x %v, want %x", hole, board, h.Value, h.BestHand, want)
+		}
+	}
+}
+
+func TestOmaha_Equity(t *testing.T) {
+	hole, _ := ParseCards([]string{"SA", "HA", "SK", "HQ"})
+	villain, _ := ParseCards([]string{"D8", "C8", "D7", "C6"})
+	community, _ := ParseCards([]string{"S7", "S2", "D9", "CT"})
+	cfg := EquityConfig{
+		Variant:   Omaha4,
+		Players:   []Range{HandRange(hole...), HandRange(villain...)},
+		Community: community,
+		NumSims:   50000,
+		Seed:      3,
+	}
+	exact, err := EnumerateEquity(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exact[0].Samples != 40 {
+		t.Errorf("deals = %d, want 40 rivers", exact[0].Samples)
+	}
+	mc, err := SimulateEquity(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(mc[0].Equity-exact[0].Equity) > 4*mc[0].EquityStdErr {
+		t.Errorf("Monte Carlo %+v too far from exact %+v", mc[0], exact[0])
+	}
+
+	sim := SimConfig{Variant: Omaha4, Hole: hole, Community: community, NumPlayers: 2}
+	if want := choose(44, 4) * 40; sim.ExactDeals() != want {
+		t.Errorf("ExactDeals = %d, want %d", sim.ExactDeals(), want)
+	}
+
+	for _, bad := range []SimConfig{
+		{Variant: Omaha4, Hole: hole[:2], NumPlayers: 2, NumSims: 10},
+		{Variant: Omaha5, Hole: append(hole, CardAt(0)), NumPlayers: 10, NumSims: 10},
+		{Variant: Omaha4, Hole: hole, NumPlayers: 2, NumSims: 10, Ranges: []Range{mustRange(t, "AA")}},
+	} {
+		if _, err := Simulate(context.Background(), bad); !IsInvalidInput(err) {
+			t.Errorf("%+v: err = %v, want invalid input", bad, err)
+		}
+	}
+}
+
+func mustRange(t *testing.T, s string) Range {
+	t.Helper()
+	r, err := ParseRange(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}